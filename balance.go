@@ -0,0 +1,36 @@
+package jap
+
+import (
+	"context"
+	"time"
+)
+
+// WatchBalance polls the balance every interval until ctx is done and calls
+// onLow when the balance drops below threshold. onLow is called once each time
+// the balance crosses below the threshold, including on the first poll, and
+// not again until the balance has risen back to at least the threshold. Polls
+// that fail, or return a balance that can't be parsed, are skipped.
+// Intervals shorter than a second are raised to a second.
+// WatchBalance blocks until ctx or the base context set by WithBaseContext is
+// done and returns the context's error.
+func (c *JAPClient) WatchBalance(ctx context.Context, interval time.Duration, threshold float64, onLow func(UserBalanceResponse)) error {
+	ctx, cancel := c.withBaseContext(ctx)
+	defer cancel()
+
+	low := false
+	for {
+		balance, err := c.GetUserBalanceContext(ctx)
+		if err == nil {
+			if amount, err := balance.BalanceFloat(); err == nil {
+				if amount < threshold && !low {
+					onLow(balance)
+				}
+				low = amount < threshold
+			}
+		}
+
+		if err := sleepContext(ctx, pollDelay(interval)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package jap
+
+import (
+	"context"
+	"sync"
+)
+
+// OrderResult is the result of adding one order in a batch.
+type OrderResult struct {
+	OrderID string
+	// Err is set instead of OrderID when the order could not be added.
+	Err error
+}
+
+// AddOrders adds the orders described by reqs, with up to concurrency requests
+// in flight at once, and returns their results in the same order as reqs. An
+// order that fails doesn't abort the others; its error is reported in its
+// result. The rate limit set by WithRateLimit applies to every request. If ctx
+// is done before all orders are added, the orders not yet started fail with
+// the context's error, which is also returned.
+func (c *JAPClient) AddOrders(ctx context.Context, reqs []OrderParams, concurrency int) ([]OrderResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]OrderResult, len(reqs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, params := range reqs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, params OrderParams) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			orderID, err := c.AddOrderWithParamsContext(ctx, params)
+			results[i] = OrderResult{OrderID: orderID, Err: err}
+		}(i, params)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
@@ -0,0 +1,76 @@
+package jap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// circuitBreaker fast-fails calls after too many consecutive failures. Once
+// open, it lets a single probe call through after the cooldown, closing again
+// if the probe succeeds. It is safe for concurrent use.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a call may proceed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record records the outcome of a call allowed by allow. Calls whose outcome
+// says nothing about the API, such as those canceled by the caller, should be
+// recorded with counted set to false.
+func (b *circuitBreaker) record(failed, counted bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !counted {
+		return
+	}
+	if !failed {
+		b.failures = 0
+		b.open = false
+		return
+	}
+
+	b.failures++
+	if b.open || b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = time.Now()
+	}
+}
+
+// sendWithBreaker sends the request through the circuit breaker configured by
+// WithCircuitBreaker, if any. Calls that fail transiently, even after retries,
+// count as failures.
+func (c *JAPClient) sendWithBreaker(ctx context.Context, bodyJSON []byte) ([]byte, int, error) {
+	if c.breaker == nil {
+		return c.sendWithRetry(ctx, bodyJSON)
+	}
+	if !c.breaker.allow() {
+		return nil, 0, ErrCircuitOpen
+	}
+
+	responseBody, statusCode, err := c.sendWithRetry(ctx, bodyJSON)
+	c.breaker.record(isTransient(ctx, statusCode, err), ctx.Err() == nil)
+	return responseBody, statusCode, err
+}
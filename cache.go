@@ -0,0 +1,64 @@
+package jap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// serviceCache holds the list of services for a limited time, separately for
+// each API key, since accounts may see different services and rates. It is
+// safe for concurrent use.
+type serviceCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]serviceCacheEntry
+}
+
+// serviceCacheEntry is the list of services cached for one API key.
+type serviceCacheEntry struct {
+	services []Service
+	expires  time.Time
+}
+
+// get returns a copy of the services cached for key, if they haven't expired.
+func (sc *serviceCache) get(key string) ([]Service, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	entry, ok := sc.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return append([]Service(nil), entry.services...), true
+}
+
+// set caches a copy of services for key for the cache's TTL.
+func (sc *serviceCache) set(key string, services []Service) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.entries == nil {
+		sc.entries = make(map[string]serviceCacheEntry)
+	}
+	sc.entries[key] = serviceCacheEntry{
+		services: append([]Service{}, services...),
+		expires:  time.Now().Add(sc.ttl),
+	}
+}
+
+// RefreshServices reloads the list of services from the API into the cache
+// enabled by WithServiceCache, for the API key the call uses, even if it
+// hasn't expired yet. Without a cache,
+// it only checks that the services can be retrieved.
+func (c *JAPClient) RefreshServices(ctx context.Context) error {
+	services, err := c.fetchServices(ctx)
+	if err != nil {
+		return err
+	}
+	if c.serviceCache != nil {
+		c.serviceCache.set(c.keyFor(ctx), services)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package jap
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// CancelResult is the result of canceling one order in a batch.
+type CancelResult struct {
+	Order  string
+	Cancel string
+	// Error is set instead of Cancel when the order could not be canceled, e.g.
+	// because its service doesn't support cancellation.
+	Error string
+}
+
+// UnmarshalJSON decodes an {"order":1,"cancel":1} object. The order and cancel
+// IDs may be strings or numbers, and cancel may instead be an {"error":"..."}
+// object. An error may also be given next to the order, as in
+// {"order":1,"error":"..."}.
+func (r *CancelResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Order  flexString      `json:"order"`
+		Cancel json.RawMessage `json:"cancel"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	cancel, errMsg, err := decodeValueOrError(raw.Cancel)
+	if err != nil {
+		return err
+	}
+	if errMsg == "" && len(raw.Error) > 0 && string(raw.Error) != "null" {
+		errMsg = errorMessage(raw.Error)
+	}
+
+	*r = CancelResult{
+		Order:  string(raw.Order),
+		Cancel: cancel,
+		Error:  errMsg,
+	}
+	return nil
+}
+
+// CancelOrders requests cancellation of several orders in a single request.
+// Orders that could not be canceled are reported through the Error field of
+// their result rather than failing the whole call.
+func (c *JAPClient) CancelOrders(orderIDs []string) ([]CancelResult, error) {
+	return c.CancelOrdersContext(context.Background(), orderIDs)
+}
+
+// CancelOrdersContext is like CancelOrders but uses the given context.
+func (c *JAPClient) CancelOrdersContext(ctx context.Context, orderIDs []string) ([]CancelResult, error) {
+	if len(orderIDs) == 0 {
+		return []CancelResult{}, nil
+	}
+
+	body := struct {
+		Key    string `json:"key"`
+		Action string `json:"action"`
+		Orders string `json:"orders"`
+	}{
+		Key:    c.keyFor(ctx),
+		Action: "cancel",
+		Orders: strings.Join(orderIDs, ","),
+	}
+	return do[[]CancelResult](ctx, c, body)
+}
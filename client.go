@@ -0,0 +1,113 @@
+package jap
+
+import (
+	"context"
+	"time"
+)
+
+// Client is the interface implemented by *JAPClient. Code that depends on
+// Client rather than *JAPClient can substitute a fake in tests.
+type Client interface {
+	// Services
+	ListServices() ([]Service, error)
+	ListServicesContext(ctx context.Context) ([]Service, error)
+	RefreshServices(ctx context.Context) error
+	StreamServices(ctx context.Context) (<-chan Service, <-chan error)
+	GetService(serviceID string) (Service, error)
+	GetServiceContext(ctx context.Context, serviceID string) (Service, error)
+	ListCategories() ([]string, error)
+	ListCategoriesContext(ctx context.Context) ([]string, error)
+	ServicesByCategory(category string) ([]Service, error)
+	ServicesByCategoryContext(ctx context.Context, category string) ([]Service, error)
+	SearchServices(query string) ([]Service, error)
+	SearchServicesContext(ctx context.Context, query string) ([]Service, error)
+	RefillableServices() ([]Service, error)
+	RefillableServicesContext(ctx context.Context) ([]Service, error)
+	CancelableServices() ([]Service, error)
+	CancelableServicesContext(ctx context.Context) ([]Service, error)
+	FilterServices(pred func(Service) bool) ([]Service, error)
+	FilterServicesContext(ctx context.Context, pred func(Service) bool) ([]Service, error)
+	EstimateOrderCost(serviceID string, quantity int) (float64, error)
+	EstimateOrderCostContext(ctx context.Context, serviceID string, quantity int) (float64, error)
+	CheapestService(category string, quantity int) (Service, float64, error)
+	CheapestServiceContext(ctx context.Context, category string, quantity int) (Service, float64, error)
+
+	// Orders
+	AddOrder(service, link string, quantity int, runs, interval *int) (string, error)
+	AddOrderContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (string, error)
+	AddOrderInt(service, link string, quantity int, runs, interval *int) (int, error)
+	AddOrderIntContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (int, error)
+	AddOrderDetailed(service, link string, quantity int, runs, interval *int) (AddOrderResponse, error)
+	AddOrderDetailedContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (AddOrderResponse, error)
+	AddOrderValidated(service, link string, quantity int, runs, interval *int) (string, error)
+	AddOrderValidatedContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (string, error)
+	AddDripFeedOrder(service, link string, quantity, runs, intervalMinutes int) (string, error)
+	AddDripFeedOrderContext(ctx context.Context, service, link string, quantity, runs, intervalMinutes int) (string, error)
+	AddOrderWithParams(params OrderParams) (string, error)
+	AddOrderWithParamsContext(ctx context.Context, params OrderParams) (string, error)
+	AddOrderAuto(ctx context.Context, serviceID string, params OrderParams) (string, error)
+	AddOrders(ctx context.Context, reqs []OrderParams, concurrency int) ([]OrderResult, error)
+	AddOrderWithKey(ctx context.Context, params OrderParams, idempotencyKey string) (string, error)
+	AddCustomCommentsOrder(service, link string, comments []string) (string, error)
+	AddCustomCommentsOrderContext(ctx context.Context, service, link string, comments []string) (string, error)
+	AddCustomCommentsPackageOrder(service, link string, comments []string) (string, error)
+	AddCustomCommentsPackageOrderContext(ctx context.Context, service, link string, comments []string) (string, error)
+	AddMentionsOrder(service, link string, usernames []string) (string, error)
+	AddMentionsOrderContext(ctx context.Context, service, link string, usernames []string) (string, error)
+	AddMentionsHashtagOrder(service, link string, quantity int, hashtags []string) (string, error)
+	AddMentionsHashtagOrderContext(ctx context.Context, service, link string, quantity int, hashtags []string) (string, error)
+	AddMentionsUserFollowersOrder(service, link string, quantity int, username string) (string, error)
+	AddMentionsUserFollowersOrderContext(ctx context.Context, service, link string, quantity int, username string) (string, error)
+	AddMentionsMediaLikersOrder(service, link string, quantity int, media string) (string, error)
+	AddMentionsMediaLikersOrderContext(ctx context.Context, service, link string, quantity int, media string) (string, error)
+	AddPollOrder(service, link string, quantity, answerNumber int) (string, error)
+	AddPollOrderContext(ctx context.Context, service, link string, quantity, answerNumber int) (string, error)
+	AddSubscriptionOrder(service, username string, min, max, posts, delay int, expiry *string) (string, error)
+	AddSubscriptionOrderContext(ctx context.Context, service, username string, min, max, posts, delay int, expiry *string) (string, error)
+	AddCommentLikesOrder(service, link string, quantity int, username string) (string, error)
+	AddCommentLikesOrderContext(ctx context.Context, service, link string, quantity int, username string) (string, error)
+	AddNamedOrder(service NamedService, link string, quantity int) (string, error)
+	AddNamedOrderContext(ctx context.Context, service NamedService, link string, quantity int) (string, error)
+	RegisterService(alias, serviceID string)
+	OrderByAlias(ctx context.Context, alias, link string, quantity int) (string, error)
+	RedditUpvote(link string, quantity int) (string, error)
+	RedditUpvoteContext(ctx context.Context, link string, quantity int) (string, error)
+
+	// Order status
+	GetOrderStatus(orderID string) (OrderStatusResponse, error)
+	GetOrderStatusContext(ctx context.Context, orderID string) (OrderStatusResponse, error)
+	GetMultipleOrderStatus(orderIDs []string) (map[string]OrderStatus, error)
+	GetMultipleOrderStatusContext(ctx context.Context, orderIDs []string) (map[string]OrderStatus, error)
+	WaitForOrder(ctx context.Context, orderID string, pollInterval time.Duration) (OrderStatus, error)
+	WaitForOrderWithStrategy(ctx context.Context, orderID string, strategy PollStrategy) (OrderStatus, error)
+	WaitForOrders(ctx context.Context, orderIDs []string, pollInterval time.Duration) (map[string]OrderStatus, error)
+	ListOrders(ctx context.Context) ([]OrderSummary, error)
+
+	// Refills and cancellation
+	CreateRefill(orderID string) (string, error)
+	CreateRefillContext(ctx context.Context, orderID string) (string, error)
+	CreateMultipleRefill(orderIDs []string) ([]RefillResult, error)
+	CreateMultipleRefillContext(ctx context.Context, orderIDs []string) ([]RefillResult, error)
+	GetRefillStatus(refillID string) (string, error)
+	GetRefillStatusContext(ctx context.Context, refillID string) (string, error)
+	GetMultipleRefillStatus(refillIDs []string) ([]RefillStatusResult, error)
+	GetMultipleRefillStatusContext(ctx context.Context, refillIDs []string) ([]RefillStatusResult, error)
+	CancelOrders(orderIDs []string) ([]CancelResult, error)
+	CancelOrdersContext(ctx context.Context, orderIDs []string) ([]CancelResult, error)
+
+	// Balance
+	GetUserBalance() (UserBalanceResponse, error)
+	GetUserBalanceContext(ctx context.Context) (UserBalanceResponse, error)
+	GetBalance() (float64, string, error)
+	GetBalanceContext(ctx context.Context) (float64, string, error)
+	WatchBalance(ctx context.Context, interval time.Duration, threshold float64, onLow func(UserBalanceResponse)) error
+
+	// Other actions
+	CallRaw(ctx context.Context, action string, params map[string]any) ([]byte, error)
+	Ping(ctx context.Context) error
+
+	// Lifecycle
+	Close() error
+}
+
+var _ Client = (*JAPClient)(nil)
@@ -0,0 +1,16 @@
+package jap
+
+// Close releases the resources held by the client. Calls made after Close
+// fail with ErrClientClosed; calls in flight are not interrupted. Idle
+// connections are closed if the client created its own transport, e.g. for
+// WithProxy, but not those of an HTTP client set with WithHTTPClient, which
+// may be shared. Close is safe to call more than once and always returns nil.
+func (c *JAPClient) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if c.ownsTransport {
+		c.client.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package jap
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// orderStatusCSVHeader is the header row written by WriteOrderStatusCSV.
+var orderStatusCSVHeader = []string{"order", "status", "charge", "start_count", "remains", "currency"}
+
+// WriteOrderStatusCSV writes statuses, as returned by GetMultipleOrderStatus,
+// to w as CSV with a header row and the columns order, status, charge,
+// start_count, remains and currency. Rows are sorted by order ID, numerically
+// for numeric IDs, so the output is the same for the same statuses.
+func WriteOrderStatusCSV(w io.Writer, statuses map[string]OrderStatus) error {
+	ids := make([]string, 0, len(statuses))
+	for id := range statuses {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, compareOrderIDs)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(orderStatusCSVHeader); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s := statuses[id]
+		if err := cw.Write([]string{id, s.Status, s.Charge, s.StartCount, s.Remains, s.Currency}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// compareOrderIDs orders numeric IDs by value, before any non-numeric IDs,
+// which are ordered as strings.
+func compareOrderIDs(a, b string) int {
+	x, errA := strconv.ParseInt(a, 10, 64)
+	y, errB := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(x, y)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return cmp.Compare(a, b)
+}
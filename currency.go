@@ -0,0 +1,21 @@
+package jap
+
+import "strconv"
+
+// CurrencyConverter converts an amount in the currency from into the currency
+// an application works in, e.g. using live exchange rates.
+type CurrencyConverter func(amount float64, from string) (float64, error)
+
+// parseAmount parses amount, reported by the API in currency, as a float64 and
+// converts it with convert if it isn't nil. It returns ErrEmptyField if amount
+// is empty.
+func parseAmount(amount, currency string, convert *CurrencyConverter) (float64, error) {
+	if amount == "" {
+		return 0, ErrEmptyField
+	}
+	v, err := strconv.ParseFloat(amount, 64)
+	if err != nil || convert == nil {
+		return v, err
+	}
+	return (*convert)(v, currency)
+}
@@ -0,0 +1,30 @@
+// Package jap is a client for the JustAnotherPanel API and JAP-compatible
+// panels.
+//
+// Create a client with New, passing the API key and any options:
+//
+//	client := jap.New(key, jap.WithTimeout(30*time.Second))
+//	balance, currency, err := client.GetBalance()
+//
+// # Cancellation
+//
+// Every method has a Context variant. The context is attached to the HTTP
+// request, so canceling it aborts a request in flight, as well as any wait for
+// the rate limiter or between retries, and the call returns promptly with an
+// error for which errors.Is(err, context.Canceled) or
+// errors.Is(err, context.DeadlineExceeded) reports true. A canceled call isn't
+// retried and isn't counted as a failure by the circuit breaker.
+//
+// # Testing
+//
+// Every request, including those made by helpers such as RedditUpvote, is sent
+// to the endpoint set by WithEndpoint, so code using the client can be tested
+// against an httptest.Server:
+//
+//	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		fmt.Fprint(w, `{"balance":"100.84292","currency":"USD"}`)
+//	}))
+//	defer srv.Close()
+//
+//	client := jap.New("test", jap.WithEndpoint(srv.URL))
+package jap
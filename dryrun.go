@@ -0,0 +1,38 @@
+package jap
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// dryRunResponse returns the synthetic successful response to a request in
+// dry-run mode, and reports whether the request should get one instead of
+// being sent. Only the actions that place or change orders get one; others,
+// which only read data, are sent even in dry-run mode.
+func (c *JAPClient) dryRunResponse(action string, body []byte) ([]byte, bool) {
+	if !c.dryRun {
+		return nil, false
+	}
+
+	var request struct {
+		Orders string `json:"orders"`
+	}
+	json.Unmarshal(body, &request)
+
+	switch action {
+	case "add":
+		return []byte(`{"order":0}`), true
+	case "refill", "cancel":
+		if request.Orders == "" {
+			return []byte(`{"` + action + `":"0"}`), true
+		}
+		var results []map[string]any
+		for _, orderID := range strings.Split(request.Orders, ",") {
+			results = append(results, map[string]any{"order": orderID, action: 0})
+		}
+		response, _ := json.Marshal(results)
+		return response, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,109 @@
+package jap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Encoding is the format request parameters are sent in.
+type Encoding int
+
+const (
+	// EncodingJSON sends parameters as a JSON object, which JAP-compatible
+	// panels commonly accept. Its fields are sorted by name.
+	EncodingJSON Encoding = iota
+	// EncodingForm sends parameters as an application/x-www-form-urlencoded
+	// body, which is what the JAP API v2 documents. Its fields are sorted by
+	// name.
+	EncodingForm
+	// EncodingQuery sends parameters in the query string of a GET request
+	// without a body, for panels that accept nothing else. The query string,
+	// including any parameters already in the endpoint, is sorted by name.
+	// Note that the API key then becomes part of the URL, which servers and
+	// proxies may log.
+	EncodingQuery
+)
+
+// marshalBody marshals a request body into a JSON object with its fields
+// sorted by name, whether body is a struct or a map, so that the bytes sent for
+// the same parameters never change, e.g. for panels that verify a signature of
+// the body.
+func marshalBody(body any) ([]byte, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bodyJSON, &fields); err != nil {
+		return bodyJSON, nil
+	}
+	// Maps are marshaled with their keys sorted.
+	return json.Marshal(fields)
+}
+
+// newRequest creates a request to endpoint carrying the parameters of the JSON
+// object bodyJSON in encoding e.
+func (e Encoding) newRequest(ctx context.Context, endpoint string, bodyJSON []byte) (*http.Request, error) {
+	switch e {
+	case EncodingForm:
+		values, err := formValues(bodyJSON)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(values.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	case EncodingQuery:
+		values, err := formValues(bodyJSON)
+		if err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		for name, value := range values {
+			query[name] = value
+		}
+		u.RawQuery = query.Encode()
+		return http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	default:
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(bodyJSON))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}
+}
+
+// formValues converts the JSON object bodyJSON into form values. Strings are
+// sent as is, null fields are left out and any other value, such as a number,
+// is sent as its JSON text.
+func formValues(bodyJSON []byte) (url.Values, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bodyJSON, &fields); err != nil {
+		return nil, err
+	}
+
+	values := make(url.Values, len(fields))
+	for name, raw := range fields {
+		if string(raw) == "null" {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(bytes.TrimSpace(raw))
+		}
+		values.Set(name, s)
+	}
+	return values, nil
+}
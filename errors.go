@@ -0,0 +1,223 @@
+package jap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrEmptyField is returned when parsing a field the API left empty. A field
+// that is present but malformed yields a *strconv.NumError instead.
+var ErrEmptyField = errors.New("jap: empty field")
+
+// ErrNonJSONResponse is returned when the API responds with something other
+// than JSON, such as an HTML error page served while it is overloaded. The
+// returned error includes the start of the response body.
+var ErrNonJSONResponse = errors.New("jap: non-JSON response")
+
+// ErrCircuitOpen is returned without calling the API while the circuit breaker
+// set by WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("jap: circuit breaker open")
+
+// ErrClientClosed is returned by calls made after Close.
+var ErrClientClosed = errors.New("jap: client closed")
+
+// ErrMissingKey is returned by NewFromEnv when the JAP_API_KEY environment
+// variable is unset or empty.
+var ErrMissingKey = errors.New("jap: JAP_API_KEY environment variable not set")
+
+// ErrInsufficientFunds matches an APIError reporting that the balance is too
+// low for an order, e.g. "Not enough funds on balance".
+var ErrInsufficientFunds = errors.New("jap: insufficient funds")
+
+// ErrInvalidService matches an APIError reporting that a service ID doesn't
+// exist, e.g. because the service was retired. The message sent by the API
+// remains available through errors.As with an APIError.
+var ErrInvalidService = errors.New("jap: invalid service")
+
+// ErrUnauthorized is returned by Ping when the API rejects the API key.
+var ErrUnauthorized = errors.New("jap: unauthorized")
+
+// ErrUnreachable is returned by Ping when the endpoint can't be reached.
+var ErrUnreachable = errors.New("jap: endpoint unreachable")
+
+// ErrUnsupported is returned when the panel doesn't implement an action, such
+// as the order listing used by ListOrders.
+var ErrUnsupported = errors.New("jap: action not supported by panel")
+
+// ErrRefillNotSupported is returned when a refill is requested for an order
+// whose service doesn't support refills.
+var ErrRefillNotSupported = errors.New("jap: refill not supported")
+
+// ErrServiceNotFound is returned when looking up a service that isn't listed.
+var ErrServiceNotFound = errors.New("jap: service not found")
+
+// ErrNoEligibleService is returned by CheapestService when no service in the
+// category can fulfill the quantity.
+var ErrNoEligibleService = errors.New("jap: no eligible service")
+
+// ErrUnknownAlias is returned when ordering by an alias that wasn't registered
+// with RegisterService.
+var ErrUnknownAlias = errors.New("jap: unknown service alias")
+
+// ErrQuantityOutOfRange is returned when an order quantity is outside the
+// minimum and maximum of its service.
+var ErrQuantityOutOfRange = errors.New("jap: quantity out of range")
+
+// ErrMissingService is returned when an order is placed without a service ID.
+var ErrMissingService = errors.New("jap: missing service")
+
+// ErrMissingParams is returned when an order lacks parameters its service type
+// requires.
+var ErrMissingParams = errors.New("jap: missing required parameters")
+
+// ErrMissingOrderID is returned when the API responds to an order without
+// either an order ID or an error, so whether the order was placed is unknown.
+var ErrMissingOrderID = errors.New("jap: response has no order ID")
+
+// ErrInvalidLink is returned when an order is placed with an empty or malformed
+// link.
+var ErrInvalidLink = errors.New("jap: invalid link")
+
+// ErrInvalidDripFeed is returned when a drip-feed order is placed with fewer
+// than 1 run or a non-positive interval.
+var ErrInvalidDripFeed = errors.New("jap: invalid drip-feed runs or interval")
+
+// ErrIncompleteDripFeed is returned when an order is placed with only one of
+// runs and interval, which drip-feed orders need both of.
+var ErrIncompleteDripFeed = errors.New("jap: drip-feed needs both runs and interval")
+
+// ErrNoComments is returned when a comments order is placed without comments.
+var ErrNoComments = errors.New("jap: no comments given")
+
+// ErrNoUsernames is returned when a mentions order is placed without the
+// usernames or username it requires.
+var ErrNoUsernames = errors.New("jap: no usernames given")
+
+// ErrNoHashtags is returned when a hashtag order is placed without hashtags.
+var ErrNoHashtags = errors.New("jap: no hashtags given")
+
+// ErrInvalidMedia is returned when a media likers order is placed with a media
+// that isn't an http or https URL.
+var ErrInvalidMedia = errors.New("jap: invalid media URL")
+
+// ErrInvalidAnswerNumber is returned when a poll order is placed with an answer
+// number below 1.
+var ErrInvalidAnswerNumber = errors.New("jap: answer number must be at least 1")
+
+// APIError is returned when the API responds with an error message instead of
+// the expected result, e.g. {"error":"Incorrect request"}. Well-known messages
+// match sentinel errors such as ErrInsufficientFunds with errors.Is.
+type APIError struct {
+	// Message is the error message returned by the API.
+	Message string
+	// Code is the HTTP status code of the response.
+	Code int
+}
+
+func (e APIError) Error() string {
+	return "jap: " + e.Message
+}
+
+// Is reports whether the error message is one the API gives for target, so
+// that, for example, errors.Is(err, ErrInsufficientFunds) can be used instead
+// of matching messages.
+func (e APIError) Is(target error) bool {
+	message := strings.ToLower(e.Message)
+	for _, kind := range apiErrorKinds {
+		if kind.err != target {
+			continue
+		}
+		for _, phrase := range kind.phrases {
+			if strings.Contains(message, phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apiErrorKinds lists the sentinel errors an APIError matches, by lower-case
+// phrases found in the messages panels use for them.
+var apiErrorKinds = []struct {
+	err     error
+	phrases []string
+}{
+	{ErrInsufficientFunds, []string{"not enough funds", "not enough balance", "insufficient funds", "insufficient balance"}},
+	{ErrInvalidService, []string{"incorrect service id", "invalid service", "service not found"}},
+}
+
+// HTTPError is returned when the API responds with a non-2xx status and a body
+// that isn't an API error. Non-2xx responses carrying an API error are returned
+// as an APIError with Code set to the status instead.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("jap: unexpected HTTP status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// checkResponse returns the APIError or HTTPError described by a response with
+// the given body and status code, or nil if the response is a success.
+func checkResponse(body []byte, statusCode int) error {
+	if apiErr, ok := parseAPIError(body, statusCode); ok {
+		return apiErr
+	}
+	if statusCode < 200 || statusCode > 299 {
+		return HTTPError{StatusCode: statusCode, Body: body}
+	}
+	return nil
+}
+
+// parseAPIError reports whether body has the error shape returned by the API
+// and, if so, returns it as an APIError. The error is usually a string, but
+// any other non-null value is reported as its JSON text.
+func parseAPIError(body []byte, code int) (APIError, bool) {
+	var response struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Error) == 0 || string(response.Error) == "null" {
+		return APIError{}, false
+	}
+
+	return APIError{Message: errorMessage(response.Error), Code: code}, true
+}
+
+// errorMessage returns the error value raw as a message. The value is usually
+// a string, but any other value is returned as its JSON text.
+func errorMessage(raw json.RawMessage) string {
+	var message string
+	if err := json.Unmarshal(raw, &message); err != nil {
+		message = string(raw)
+	}
+	return message
+}
+
+// snippetLength is the number of bytes of a response body included in errors.
+const snippetLength = 200
+
+// isJSONResponse reports whether a response with the given content type and
+// body is JSON rather than, say, an HTML page. It goes by the body, since many
+// PHP panels send JSON labeled text/html; the content type only decides for an
+// empty body.
+func isJSONResponse(contentType string, body []byte) bool {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return !strings.Contains(strings.ToLower(contentType), "text/html")
+	}
+	return body[0] != '<' && json.Valid(body)
+}
+
+// nonJSONResponseError returns an error wrapping ErrNonJSONResponse with the
+// start of body.
+func nonJSONResponseError(body []byte) error {
+	if len(body) > snippetLength {
+		body = body[:snippetLength]
+	}
+	return fmt.Errorf("%w: %q", ErrNonJSONResponse, body)
+}
@@ -1,3 +1,5 @@
 module github.com/bjornpagen/jap-api
 
 go 1.21.5
+
+require golang.org/x/time v0.10.0
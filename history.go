@@ -0,0 +1,154 @@
+package jap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// OrderSummary is an order as listed by ListOrders. Panels differ in what they
+// report, so any field may be empty.
+type OrderSummary struct {
+	OrderID    string
+	Service    string
+	Link       string
+	Quantity   string
+	Status     string
+	Charge     string
+	Currency   string
+	StartCount string
+	Remains    string
+	Created    string
+}
+
+// UnmarshalJSON decodes an order from a listing, accepting the field names
+// and value types in use across panels.
+func (o *OrderSummary) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Order      flexString `json:"order"`
+		ID         flexString `json:"id"`
+		Service    flexString `json:"service"`
+		Link       string     `json:"link"`
+		Quantity   flexString `json:"quantity"`
+		Status     string     `json:"status"`
+		Charge     flexString `json:"charge"`
+		Currency   string     `json:"currency"`
+		StartCount flexString `json:"start_count"`
+		Remains    flexString `json:"remains"`
+		Date       string     `json:"date"`
+		Created    string     `json:"created"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*o = OrderSummary{
+		OrderID:    string(raw.Order),
+		Service:    string(raw.Service),
+		Link:       raw.Link,
+		Quantity:   string(raw.Quantity),
+		Status:     raw.Status,
+		Charge:     string(raw.Charge),
+		Currency:   raw.Currency,
+		StartCount: string(raw.StartCount),
+		Remains:    string(raw.Remains),
+		Created:    raw.Created,
+	}
+	if o.OrderID == "" {
+		o.OrderID = string(raw.ID)
+	}
+	if o.Created == "" {
+		o.Created = raw.Date
+	}
+	return nil
+}
+
+// listOrdersActions are the actions tried by ListOrders, in order.
+var listOrdersActions = []string{"orders", "order_list"}
+
+// ListOrders retrieves the recent orders of the account from panels that
+// support listing them, which the JAP API itself doesn't. It tries the
+// "orders" and "order_list" actions in turn and returns an error wrapping
+// ErrUnsupported if the panel rejects both.
+func (c *JAPClient) ListOrders(ctx context.Context) ([]OrderSummary, error) {
+	var err error
+	for _, action := range listOrdersActions {
+		body := struct {
+			Key    string `json:"key"`
+			Action string `json:"action"`
+		}{
+			Key:    c.keyFor(ctx),
+			Action: action,
+		}
+		var response json.RawMessage
+		response, err = do[json.RawMessage](ctx, c, body)
+		if err == nil {
+			return decodeOrderList(response)
+		}
+		if !isUnsupportedAction(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrUnsupported, err)
+}
+
+// decodeOrderList decodes a list of orders given as an array, an object keyed
+// by order ID, or either of those wrapped in an {"orders":...} object.
+func decodeOrderList(data json.RawMessage) ([]OrderSummary, error) {
+	data = bytes.TrimSpace(data)
+	var wrapped struct {
+		Orders json.RawMessage `json:"orders"`
+	}
+	if bytes.HasPrefix(data, []byte("{")) && json.Unmarshal(data, &wrapped) == nil && len(wrapped.Orders) > 0 {
+		data = bytes.TrimSpace(wrapped.Orders)
+	}
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		var orders []OrderSummary
+		if err := json.Unmarshal(data, &orders); err != nil {
+			return nil, err
+		}
+		return orders, nil
+	}
+
+	var byID map[string]OrderSummary
+	if err := json.Unmarshal(data, &byID); err != nil {
+		return nil, err
+	}
+	orders := make([]OrderSummary, 0, len(byID))
+	for id, order := range byID {
+		if order.OrderID == "" {
+			order.OrderID = id
+		}
+		orders = append(orders, order)
+	}
+	slices.SortFunc(orders, func(a, b OrderSummary) int {
+		return compareOrderIDs(a.OrderID, b.OrderID)
+	})
+	return orders, nil
+}
+
+// isUnsupportedAction reports whether err means the panel doesn't implement
+// the requested action.
+func isUnsupportedAction(err error) bool {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		message := strings.ToLower(apiErr.Message)
+		for _, s := range []string{"incorrect request", "action", "not supported", "unsupported"} {
+			if strings.Contains(message, s) {
+				return true
+			}
+		}
+		return false
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusNotImplemented
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package jap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// IdempotencyStore records the order ID placed for each idempotency key. Use a
+// persistent implementation to keep orders from being placed twice across
+// process restarts.
+type IdempotencyStore interface {
+	// Get returns the order ID recorded for key, if any.
+	Get(ctx context.Context, key string) (orderID string, ok bool, err error)
+	// Put records the order ID placed for key.
+	Put(ctx context.Context, key, orderID string) error
+}
+
+// memoryIdempotencyStore is an IdempotencyStore that keeps keys in memory.
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	orders map[string]string
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore that keeps keys in
+// memory, for as long as the process runs. It is the default store.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{orders: make(map[string]string)}
+}
+
+func (s *memoryIdempotencyStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orderID, ok := s.orders[key]
+	return orderID, ok, nil
+}
+
+func (s *memoryIdempotencyStore) Put(ctx context.Context, key, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[key] = orderID
+	return nil
+}
+
+// AddOrderWithKey is like AddOrderWithParamsContext, but if an order was
+// already placed with the same idempotency key, it returns that order's ID
+// instead of placing the order again. Keys are recorded in the store set by
+// WithIdempotencyStore, in memory by default. Concurrent calls with the same
+// key are serialized. Idempotency keys are scoped to the API key used for the
+// call. In dry-run mode, the store is neither consulted nor updated, so a
+// rehearsal can't keep the real order from being placed later.
+func (c *JAPClient) AddOrderWithKey(ctx context.Context, params OrderParams, idempotencyKey string) (string, error) {
+	if c.dryRun {
+		return c.AddOrderWithParamsContext(ctx, params)
+	}
+
+	storeKey := c.idempotencyStoreKey(ctx, idempotencyKey)
+	unlock := c.lockIdempotencyKey(storeKey)
+	defer unlock()
+
+	orderID, ok, err := c.idempotencyStore.Get(ctx, storeKey)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return orderID, nil
+	}
+
+	orderID, err = c.AddOrderWithParamsContext(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.idempotencyStore.Put(ctx, storeKey, orderID); err != nil {
+		return orderID, err
+	}
+	return orderID, nil
+}
+
+// idempotencyStoreKey returns the key under which an order placed with
+// idempotencyKey is recorded. It is prefixed with a hash of the API key used
+// for the call, so accounts served through ContextWithKey can reuse idempotency
+// keys without seeing each other's orders, and the API key isn't stored.
+func (c *JAPClient) idempotencyStoreKey(ctx context.Context, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(c.keyFor(ctx)))
+	return hex.EncodeToString(sum[:8]) + ":" + idempotencyKey
+}
+
+// keyLock is a mutex for one idempotency key, with the number of callers
+// holding or waiting for it.
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockIdempotencyKey locks key and returns a function unlocking it.
+func (c *JAPClient) lockIdempotencyKey(key string) func() {
+	c.keyLocksMu.Lock()
+	if c.keyLocks == nil {
+		c.keyLocks = make(map[string]*keyLock)
+	}
+	l, ok := c.keyLocks[key]
+	if !ok {
+		l = &keyLock{}
+		c.keyLocks[key] = l
+	}
+	l.refs++
+	c.keyLocksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		c.keyLocksMu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(c.keyLocks, key)
+		}
+		c.keyLocksMu.Unlock()
+	}
+}
@@ -2,24 +2,135 @@ package jap
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
+// Version is the version of this library, reported in the default User-Agent.
+const Version = "0.1.0"
+
 // JAPClient is a client for the JustAnotherPanel API.
 type JAPClient struct {
-	key      string
-	endpoint string
+	key       string
+	endpoint  string
+	client    *http.Client
+	timeout   time.Duration
+	userAgent string
+	headers   http.Header
+	proxy     string
+	logger    Logger
+	observer  Observer
+	tracer    Tracer
+	dryRun    bool
+	encoding  Encoding
+	convert   *CurrencyConverter
+	baseCtx   context.Context
+	signer    RequestSigner
+
+	strictDecoding bool
+
+	statusBatchSize int
+
+	serviceCache *serviceCache
+
+	maxAttempts int
+	retryDelay  time.Duration
+	limiter     *rate.Limiter
+	breaker     *circuitBreaker
+
+	idempotencyStore IdempotencyStore
+	keyLocksMu       sync.Mutex
+	keyLocks         map[string]*keyLock
+
+	aliasesMu sync.RWMutex
+	aliases   map[string]string
+
+	ownsTransport bool
+	closed        atomic.Bool
 }
 
-// New creates a new JAPClient with the given API key.
-func New(key string) JAPClient {
-	return JAPClient{
-		key:      key,
-		endpoint: "https://justanotherpanel.com/api/v2",
+// New creates a new JAPClient with the given API key and options. By default
+// it sends JSON bodies to the JustAnotherPanel endpoint, as it always has;
+// WithPanel(JAPPanel) sends form-encoded bodies instead, as the JAP API
+// documents.
+func New(key string, opts ...Option) *JAPClient {
+	c := &JAPClient{
+		key:       key,
+		endpoint:  "https://justanotherpanel.com/api/v2",
+		client:    defaultHTTPClient,
+		userAgent: "jap-api-go/" + Version,
+
+		statusBatchSize: defaultStatusBatchSize,
+
+		idempotencyStore: NewMemoryIdempotencyStore(),
+		aliases: map[string]string{
+			RedditUpvoteService.Name: RedditUpvoteService.ID,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.proxy != "" {
+		proxied := withProxy(c.client, c.proxy)
+		c.ownsTransport = proxied != c.client
+		c.client = proxied
+	}
+	return c
+}
+
+// Environment variables read by NewFromEnv.
+const (
+	EnvAPIKey   = "JAP_API_KEY"
+	EnvEndpoint = "JAP_ENDPOINT"
+)
+
+// NewFromEnv creates a new JAPClient with the API key from the JAP_API_KEY
+// environment variable and the given options. If JAP_ENDPOINT is set, it
+// overrides the endpoint, including one set by the options. It returns
+// ErrMissingKey if JAP_API_KEY is unset or empty.
+func NewFromEnv(opts ...Option) (*JAPClient, error) {
+	key := os.Getenv(EnvAPIKey)
+	if key == "" {
+		return nil, ErrMissingKey
+	}
+	if endpoint := os.Getenv(EnvEndpoint); endpoint != "" {
+		opts = append(opts[:len(opts):len(opts)], WithEndpoint(endpoint))
+	}
+	return New(key, opts...), nil
+}
+
+// Endpoint returns the API endpoint requests are sent to.
+func (c *JAPClient) Endpoint() string {
+	return c.endpoint
+}
+
+// Timeout returns the default per-request timeout set by WithTimeout, or 0 if
+// there is none.
+func (c *JAPClient) Timeout() time.Duration {
+	return c.timeout
+}
+
+// UserAgent returns the User-Agent header sent with each request.
+func (c *JAPClient) UserAgent() string {
+	return c.userAgent
+}
+
+// DryRun reports whether dry-run mode is enabled by WithDryRun.
+func (c *JAPClient) DryRun() bool {
+	return c.dryRun
 }
 
 // Service represents the structure of each service in the API response.
@@ -35,63 +146,252 @@ type Service struct {
 	Cancel   bool   `json:"cancel"`
 }
 
+// UnmarshalJSON decodes a service as listed by the API. As panels differ,
+// Service, Rate, Min and Max may be strings or numbers, and Refill and Cancel
+// may be bools, strings such as "true" or "1", or numbers.
+func (s *Service) UnmarshalJSON(data []byte) error {
+	type service Service
+	var raw struct {
+		service
+		Service flexString `json:"service"`
+		Rate    flexString `json:"rate"`
+		Min     flexString `json:"min"`
+		Max     flexString `json:"max"`
+		Refill  flexBool   `json:"refill"`
+		Cancel  flexBool   `json:"cancel"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Service(raw.service)
+	s.Service = string(raw.Service)
+	s.Rate = string(raw.Rate)
+	s.Min = string(raw.Min)
+	s.Max = string(raw.Max)
+	s.Refill = bool(raw.Refill)
+	s.Cancel = bool(raw.Cancel)
+	return nil
+}
+
 // ListServices retrieves the list of services from the API.
 func (c *JAPClient) ListServices() ([]Service, error) {
+	return c.ListServicesContext(context.Background())
+}
+
+// ListServicesContext is like ListServices but uses the given context.
+func (c *JAPClient) ListServicesContext(ctx context.Context) ([]Service, error) {
+	if c.serviceCache == nil {
+		return c.fetchServices(ctx)
+	}
+	key := c.keyFor(ctx)
+	if services, ok := c.serviceCache.get(key); ok {
+		return services, nil
+	}
+
+	services, err := c.fetchServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.serviceCache.set(key, services)
+	return services, nil
+}
+
+// fetchServices retrieves the list of services from the API, bypassing the cache.
+func (c *JAPClient) fetchServices(ctx context.Context) ([]Service, error) {
 	body := struct {
 		Key    string `json:"key"`
 		Action string `json:"action"`
 	}{
-		Key:    c.key,
+		Key:    c.keyFor(ctx),
 		Action: "services",
 	}
-	bytes, err := c.post(body)
+	return do[[]Service](ctx, c, body)
+}
+
+// AddOrder adds an order with the given parameters and returns the order ID as a string.
+// runs and interval enable drip-feed and must be given together; if only one
+// is, ErrIncompleteDripFeed is returned without placing the order.
+func (c *JAPClient) AddOrder(service, link string, quantity int, runs, interval *int) (string, error) {
+	return c.AddOrderContext(context.Background(), service, link, quantity, runs, interval)
+}
+
+// AddOrderContext is like AddOrder but uses the given context.
+func (c *JAPClient) AddOrderContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (string, error) {
+	response, err := c.AddOrderDetailedContext(ctx, service, link, quantity, runs, interval)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return response.OrderID, nil
+}
 
-	var response []Service
-	err = json.Unmarshal(bytes, &response)
+// AddOrderInt is like AddOrder but returns the order ID as an int. It fails if
+// the panel uses order IDs that aren't numbers, even though the order was
+// placed; AddOrder works with any order ID.
+func (c *JAPClient) AddOrderInt(service, link string, quantity int, runs, interval *int) (int, error) {
+	return c.AddOrderIntContext(context.Background(), service, link, quantity, runs, interval)
+}
+
+// AddOrderIntContext is like AddOrderInt but uses the given context.
+func (c *JAPClient) AddOrderIntContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (int, error) {
+	response, err := c.AddOrderDetailedContext(ctx, service, link, quantity, runs, interval)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	return strconv.Atoi(response.OrderID)
+}
 
-	return response, nil
+// AddOrderResponse is the API's response to an order.
+type AddOrderResponse struct {
+	OrderID string
+	// Charge and Currency are what the order cost. Only some panels return
+	// them; they are empty otherwise.
+	Charge   string
+	Currency string
+	// convert is the converter set with WithCurrencyConverter on the client
+	// that returned the value, if any. It is a pointer to keep the type
+	// comparable.
+	convert *CurrencyConverter
 }
 
-// AddOrder adds an order with the given parameters and returns the order ID as a string.
-func (c *JAPClient) AddOrder(service, link string, quantity int, runs, interval *int) (string, error) {
-	orderRequest := struct {
-		Key      string `json:"key"`
-		Action   string `json:"action"`
-		Service  string `json:"service"`
-		Link     string `json:"link"`
-		Quantity int    `json:"quantity"`
-		Runs     *int   `json:"runs,omitempty"`
-		Interval *int   `json:"interval,omitempty"`
-	}{
-		Key:      c.key,
-		Action:   "add",
+// ChargeFloat parses Charge as a float64. It returns ErrEmptyField if the
+// panel didn't return a charge. If a converter was set with
+// WithCurrencyConverter, the charge is converted from Currency with it.
+func (r AddOrderResponse) ChargeFloat() (float64, error) {
+	return parseAmount(r.Charge, r.Currency, r.convert)
+}
+
+// AddOrderDetailed is like AddOrder but returns the API's whole response,
+// including the charge of the order if the panel reports it.
+func (c *JAPClient) AddOrderDetailed(service, link string, quantity int, runs, interval *int) (AddOrderResponse, error) {
+	return c.AddOrderDetailedContext(context.Background(), service, link, quantity, runs, interval)
+}
+
+// AddOrderDetailedContext is like AddOrderDetailed but uses the given context.
+func (c *JAPClient) AddOrderDetailedContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (AddOrderResponse, error) {
+	if err := validateLink(link); err != nil {
+		return AddOrderResponse{}, err
+	}
+
+	return c.placeOrder(ctx, OrderParams{
 		Service:  service,
 		Link:     link,
 		Quantity: quantity,
 		Runs:     runs,
 		Interval: interval,
-	}
+	})
+}
 
-	bytes, err := c.post(orderRequest)
+// AddOrderValidated is like AddOrder but first checks quantity against the
+// minimum and maximum of the service, returning an error wrapping
+// ErrQuantityOutOfRange without placing the order if it is out of range. The
+// service is looked up with GetService, so the check is free of network calls
+// when WithServiceCache is used.
+func (c *JAPClient) AddOrderValidated(service, link string, quantity int, runs, interval *int) (string, error) {
+	return c.AddOrderValidatedContext(context.Background(), service, link, quantity, runs, interval)
+}
+
+// AddOrderValidatedContext is like AddOrderValidated but uses the given context.
+func (c *JAPClient) AddOrderValidatedContext(ctx context.Context, service, link string, quantity int, runs, interval *int) (string, error) {
+	s, err := c.GetServiceContext(ctx, service)
 	if err != nil {
 		return "", err
 	}
+	if err := s.CheckQuantity(quantity); err != nil {
+		return "", err
+	}
 
-	var response struct {
-		OrderID int `json:"order"`
+	return c.AddOrderContext(ctx, service, link, quantity, runs, interval)
+}
+
+// addOrder posts an add order request and returns the API's response.
+func (c *JAPClient) addOrder(ctx context.Context, orderRequest interface{}) (AddOrderResponse, error) {
+	type addOrderResponse struct {
+		OrderID  flexString `json:"order"`
+		Charge   flexString `json:"charge"`
+		Currency string     `json:"currency"`
 	}
-	err = json.Unmarshal(bytes, &response)
+	// Errors are detected by do, before the order ID is read.
+	response, err := do[addOrderResponse](ctx, c, orderRequest)
 	if err != nil {
-		return "", err
+		return AddOrderResponse{}, err
+	}
+	if response.OrderID == "" {
+		return AddOrderResponse{}, ErrMissingOrderID
+	}
+
+	return AddOrderResponse{
+		OrderID:  string(response.OrderID),
+		Charge:   string(response.Charge),
+		Currency: response.Currency,
+		convert:  c.convert,
+	}, nil
+}
+
+// flexString is a string that may be encoded in JSON as either a string or a
+// number, as panels are inconsistent about IDs.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*s = flexString(v)
+		return nil
 	}
 
-	return strconv.Itoa(response.OrderID), nil
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*s = flexString(n)
+	return nil
+}
+
+// flexBool is a bool that may be encoded in JSON as a bool, a string such as
+// "true" or "1", or a number, which is true unless 0.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	switch data[0] {
+	case 't', 'f':
+		var v bool
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*b = flexBool(v)
+		return nil
+	case '"':
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v == "" {
+			*b = false
+			return nil
+		}
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*b = flexBool(parsed)
+		return nil
+	case 'n':
+		return nil
+	}
+
+	var n float64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*b = n != 0
+	return nil
 }
 
 // OrderStatusResponse represents the JSON structure of the response for the order status request.
@@ -101,80 +401,336 @@ type OrderStatusResponse struct {
 
 // GetOrderStatus checks the status of an order with the given order ID and returns the status.
 func (c *JAPClient) GetOrderStatus(orderID string) (OrderStatusResponse, error) {
+	return c.GetOrderStatusContext(context.Background(), orderID)
+}
+
+// GetOrderStatusContext is like GetOrderStatus but uses the given context.
+func (c *JAPClient) GetOrderStatusContext(ctx context.Context, orderID string) (OrderStatusResponse, error) {
 	body := struct {
 		Key    string `json:"key"`
 		Action string `json:"action"`
 		Order  string `json:"order"`
 	}{
-		Key:    c.key,
+		Key:    c.keyFor(ctx),
 		Action: "status",
 		Order:  orderID,
 	}
-	bytes, err := c.post(body)
-	if err != nil {
-		return OrderStatusResponse{}, err
+	response, err := do[OrderStatusResponse](ctx, c, body)
+	for id, status := range response.OrderStatus {
+		status.convert = c.convert
+		response.OrderStatus[id] = status
 	}
+	return response, err
+}
 
-	var response OrderStatusResponse
-	err = json.Unmarshal(bytes, &response)
-	if err != nil {
-		return OrderStatusResponse{}, err
+// defaultStatusBatchSize is the number of order IDs GetMultipleOrderStatus
+// sends per request unless set with WithStatusBatchSize.
+const defaultStatusBatchSize = 100
+
+// GetMultipleOrderStatus checks the status of several orders and returns the
+// statuses keyed by order ID. Orders the API could not look up are included
+// with their Error field set. The IDs are sent in batches of the size set by
+// WithStatusBatchSize, 100 by default; if a batch fails, its error is returned
+// along with no statuses.
+func (c *JAPClient) GetMultipleOrderStatus(orderIDs []string) (map[string]OrderStatus, error) {
+	return c.GetMultipleOrderStatusContext(context.Background(), orderIDs)
+}
+
+// GetMultipleOrderStatusContext is like GetMultipleOrderStatus but uses the given context.
+func (c *JAPClient) GetMultipleOrderStatusContext(ctx context.Context, orderIDs []string) (map[string]OrderStatus, error) {
+	if len(orderIDs) == 0 {
+		return map[string]OrderStatus{}, nil
 	}
 
-	return response, nil
+	batchSize := c.statusBatchSize
+	if batchSize <= 0 {
+		batchSize = len(orderIDs)
+	}
+	statuses := make(map[string]OrderStatus, len(orderIDs))
+	for start := 0; start < len(orderIDs); start += batchSize {
+		end := min(start+batchSize, len(orderIDs))
+		body := struct {
+			Key    string `json:"key"`
+			Action string `json:"action"`
+			Orders string `json:"orders"`
+		}{
+			Key:    c.keyFor(ctx),
+			Action: "status",
+			Orders: strings.Join(orderIDs[start:end], ","),
+		}
+		batch, err := do[map[string]OrderStatus](ctx, c, body)
+		if err != nil {
+			return nil, err
+		}
+		for id, status := range batch {
+			status.convert = c.convert
+			statuses[id] = status
+		}
+	}
+	return statuses, nil
 }
 
 // GetUserBalance retrieves the user's balance from the API.
 func (c *JAPClient) GetUserBalance() (UserBalanceResponse, error) {
+	return c.GetUserBalanceContext(context.Background())
+}
+
+// GetUserBalanceContext is like GetUserBalance but uses the given context.
+func (c *JAPClient) GetUserBalanceContext(ctx context.Context) (UserBalanceResponse, error) {
 	body := struct {
 		Key    string `json:"key"`
 		Action string `json:"action"`
 	}{
-		Key:    c.key,
+		Key:    c.keyFor(ctx),
 		Action: "balance",
 	}
-	bytes, err := c.post(body)
+	response, err := do[UserBalanceResponse](ctx, c, body)
+	response.convert = c.convert
+	return response, err
+}
+
+// GetBalance retrieves the user's balance from the API and returns it as a
+// float64 along with its currency. The balance is converted if a converter was
+// set with WithCurrencyConverter, but the currency is the one the API reported.
+func (c *JAPClient) GetBalance() (float64, string, error) {
+	return c.GetBalanceContext(context.Background())
+}
+
+// GetBalanceContext is like GetBalance but uses the given context.
+func (c *JAPClient) GetBalanceContext(ctx context.Context) (float64, string, error) {
+	response, err := c.GetUserBalanceContext(ctx)
 	if err != nil {
-		return UserBalanceResponse{}, err
+		return 0, "", err
 	}
 
-	var response UserBalanceResponse
-	err = json.Unmarshal(bytes, &response)
+	balance, err := response.BalanceFloat()
 	if err != nil {
-		return UserBalanceResponse{}, err
+		return 0, "", err
 	}
 
-	return response, nil
+	return balance, response.Currency, nil
+}
+
+// CallRaw calls the given action with params, for actions this package doesn't
+// cover, and returns the raw JSON response. The key and action are set by the
+// client and take precedence over params. API errors are detected and returned
+// as for typed methods.
+func (c *JAPClient) CallRaw(ctx context.Context, action string, params map[string]any) ([]byte, error) {
+	body := make(map[string]any, len(params)+2)
+	for k, v := range params {
+		body[k] = v
+	}
+	body["key"] = c.keyFor(ctx)
+	body["action"] = action
+
+	return c.post(ctx, body)
 }
 
 // post is a helper method to perform POST requests for the JAPClient.
-func (c *JAPClient) post(body interface{}) ([]byte, error) {
-	bodyJSON, err := json.Marshal(body)
+func (c *JAPClient) post(ctx context.Context, body interface{}) ([]byte, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	bodyJSON, err := marshalBody(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(bodyJSON))
+	action := requestAction(bodyJSON)
+	var span Span
+	if c.tracer != nil {
+		ctx, span = c.tracer.Start(ctx, "jap."+action, spanAttributes(action, bodyJSON))
+	}
+
+	start := time.Now()
+	var (
+		responseBody []byte
+		statusCode   int
+	)
+	if response, ok := c.dryRunResponse(action, bodyJSON); ok {
+		responseBody, statusCode = response, http.StatusOK
+	} else {
+		responseBody, statusCode, err = c.sendWithBreaker(ctx, bodyJSON)
+	}
+	if err == nil {
+		err = checkResponse(responseBody, statusCode)
+	}
+	err = redactError(err, c.key, c.keyFor(ctx))
+	if info := responseInfoFrom(ctx); info != nil {
+		info.Duration = time.Since(start)
+		info.StatusCode = statusCode
+	}
+	if c.observer != nil {
+		c.observer.ObserveRequest(action, time.Since(start), err)
+	}
+	if span != nil {
+		span.End(err)
+	}
+	if c.logger != nil {
+		c.logger(redactKey(bodyJSON), responseBody, err)
+	}
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// The response type will depend on the method calling post, so we return the raw JSON
+	// and let the calling method handle unmarshalling.
+	return responseBody, nil
+}
+
+// do posts body and unmarshals the response into a T. API errors are detected
+// by post, before unmarshalling.
+func do[T any](ctx context.Context, c *JAPClient, body any) (T, error) {
+	var response T
+	responseBody, err := c.post(ctx, body)
 	if err != nil {
-		return nil, err
+		return response, err
+	}
+
+	if err := c.decode(responseBody, &response); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return response, nil
+}
+
+// decode unmarshals a response body into v, rejecting fields v has no field
+// for if WithStrictDecoding is enabled.
+func (c *JAPClient) decode(data []byte, v any) error {
+	if !c.strictDecoding {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("jap: invalid data after top-level value")
+	}
+	return nil
+}
+
+// send performs a single request with the given body and returns the response
+// body, status code and headers.
+func (c *JAPClient) send(ctx context.Context, bodyJSON []byte) ([]byte, int, http.Header, error) {
+	resp, done, err := c.open(ctx, bodyJSON)
+	if err != nil {
+		return nil, 0, nil, err
 	}
-	defer resp.Body.Close()
+	defer done()
 
 	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+
+	// Overloaded panels may serve an HTML page with a 200 status.
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 && !isJSONResponse(resp.Header.Get("Content-Type"), responseBody) {
+		return responseBody, resp.StatusCode, resp.Header, nonJSONResponseError(responseBody)
+	}
+
+	return responseBody, resp.StatusCode, resp.Header, nil
+}
+
+// open performs a single request with the given body, applying the timeout set
+// by WithTimeout and the rate limit set by WithRateLimit, and returns the
+// response with its body decompressed. The returned function closes the body
+// and releases the timeout; it must be called once the body has been read.
+func (c *JAPClient) open(ctx context.Context, bodyJSON []byte) (*http.Response, func(), error) {
+	cancel := func() {}
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			cancel()
+			// Wait fails early, without wrapping the context error, when the
+			// deadline would pass before a token is available.
+			if ctx.Err() == nil {
+				err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+			}
+			return nil, nil, err
+		}
+	}
+
+	req, err := c.newRequest(ctx, bodyJSON)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	r, err := responseReader(resp)
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, nil, err
+	}
+	body := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{r, body}
+	return resp, func() {
+		body.Close()
+		cancel()
+	}, nil
+}
+
+// newRequest creates a request to the endpoint with the parameters of the given
+// JSON body, encoded as set by WithEncoding, and the configured headers.
+func (c *JAPClient) newRequest(ctx context.Context, bodyJSON []byte) (*http.Request, error) {
+	req, err := c.encoding.newRequest(ctx, c.endpoint, bodyJSON)
 	if err != nil {
 		return nil, err
 	}
+	contentType := req.Header.Get("Content-Type")
+	req.Header.Set("User-Agent", c.userAgent)
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Del("Content-Type")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.signer != nil {
+		if err := c.sign(req); err != nil {
+			return nil, err
+		}
+	}
+	// Setting Accept-Encoding disables the transparent decompression of
+	// http.Transport, so gzip responses are decompressed by responseReader
+	// instead. This way compression works with any transport.
+	req.Header.Set("Accept-Encoding", "gzip")
 
-	// The response type will depend on the method calling post, so we return the raw JSON
-	// and let the calling method handle unmarshalling.
-	return responseBody, nil
+	return req, nil
+}
+
+// responseReader returns a reader for the body of resp, decompressing it if it
+// is gzip-encoded.
+func responseReader(resp *http.Response) (io.Reader, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		return bytes.NewReader(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gz, nil
 }
 
 // OrderStatus details for an order.
@@ -185,14 +741,35 @@ type OrderStatus struct {
 	Remains    string `json:"remains,omitempty"`
 	Currency   string `json:"currency,omitempty"`
 	Error      string `json:"error,omitempty"`
+	// Extra holds any other fields the panel reported, such as drip-feed
+	// progress, keyed by name. It is nil if there are none.
+	Extra map[string]json.RawMessage `json:"-"`
+	// convert is the client's converter, as in AddOrderResponse.
+	convert *CurrencyConverter
 }
 
 // UserBalanceResponse represents the JSON structure of the response for the user balance request.
 type UserBalanceResponse struct {
 	Balance  string `json:"balance"`
 	Currency string `json:"currency"`
+	// convert is the client's converter, as in AddOrderResponse.
+	convert *CurrencyConverter
+}
+
+// BalanceFloat returns the balance as a float64. It returns ErrEmptyField if the
+// API didn't report a balance. If the balance was returned by a client with a
+// converter set by WithCurrencyConverter, it is converted from Currency with
+// it.
+func (b UserBalanceResponse) BalanceFloat() (float64, error) {
+	return parseAmount(b.Balance, b.Currency, b.convert)
 }
 
+// RedditUpvote places a Reddit upvote order for the given link.
 func (c *JAPClient) RedditUpvote(link string, quantity int) (string, error) {
-	return c.AddOrder("6228", link, quantity, nil, nil)
+	return c.RedditUpvoteContext(context.Background(), link, quantity)
+}
+
+// RedditUpvoteContext is like RedditUpvote but uses the given context.
+func (c *JAPClient) RedditUpvoteContext(ctx context.Context, link string, quantity int) (string, error) {
+	return c.AddNamedOrderContext(ctx, RedditUpvoteService, link, quantity)
 }
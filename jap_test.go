@@ -0,0 +1,224 @@
+package jap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// prompt is how long a canceled call may take to return.
+const prompt = time.Second
+
+// newBlockingServer returns a server whose handler blocks until the test ends.
+func newBlockingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	// Cleanups run last-in first-out, so the handler is unblocked before
+	// Close waits for it.
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(unblock) })
+	return srv
+}
+
+// cancelAfter returns a context that is canceled after d.
+func cancelAfter(t *testing.T, d time.Duration) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(d, cancel)
+	t.Cleanup(func() {
+		timer.Stop()
+		cancel()
+	})
+	return ctx
+}
+
+// checkAborted fails the test unless err matches target and the call returned
+// promptly.
+func checkAborted(t *testing.T, err, target error, elapsed time.Duration) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("err = %v, want %v", err, target)
+	}
+	if elapsed > prompt {
+		t.Errorf("call returned after %v, want under %v", elapsed, prompt)
+	}
+}
+
+func TestCancelAbortsInFlightRequest(t *testing.T) {
+	srv := newBlockingServer(t)
+	c := New("test", WithEndpoint(srv.URL))
+
+	start := time.Now()
+	_, err := c.GetUserBalanceContext(cancelAfter(t, 50*time.Millisecond))
+	checkAborted(t, err, context.Canceled, time.Since(start))
+}
+
+func TestTimeoutAbortsInFlightRequest(t *testing.T) {
+	srv := newBlockingServer(t)
+	c := New("test", WithEndpoint(srv.URL), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := c.GetUserBalanceContext(context.Background())
+	checkAborted(t, err, context.DeadlineExceeded, time.Since(start))
+}
+
+func TestCancelInterruptsRetryWait(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL), WithRetry(5, 10*time.Second))
+
+	start := time.Now()
+	_, err := c.GetUserBalanceContext(cancelAfter(t, 50*time.Millisecond))
+	checkAborted(t, err, context.Canceled, time.Since(start))
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestCancelInterruptsRateLimiterWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"balance":"1.00","currency":"USD"}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL), WithRateLimit(0.01))
+
+	// The first call takes the only token; the next is 100 seconds away.
+	if _, err := c.GetUserBalance(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err := c.GetUserBalanceContext(cancelAfter(t, 50*time.Millisecond))
+	checkAborted(t, err, context.Canceled, time.Since(start))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = c.GetUserBalanceContext(ctx)
+	checkAborted(t, err, context.DeadlineExceeded, time.Since(start))
+}
+
+func TestJSONLabeledHTMLIsAccepted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte(`{"order":23501}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL))
+
+	orderID, err := c.AddOrder("1", "https://example.com/post", 100, nil, nil)
+	if err != nil || orderID != "23501" {
+		t.Errorf("AddOrder = %q, %v, want %q, nil", orderID, err, "23501")
+	}
+}
+
+func TestHTMLPageIsNonJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte(`<html><body>Overloaded</body></html>`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL))
+
+	if _, err := c.GetUserBalance(); !errors.Is(err, ErrNonJSONResponse) {
+		t.Errorf("err = %v, want %v", err, ErrNonJSONResponse)
+	}
+}
+
+func TestRateLimitedOrderIsRetried(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"order":23501}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL), WithRetry(3, time.Millisecond))
+
+	orderID, err := c.AddOrder("1", "https://example.com/post", 100, nil, nil)
+	if err != nil || orderID != "23501" {
+		t.Errorf("AddOrder = %q, %v, want %q, nil", orderID, err, "23501")
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
+
+func TestFailedOrderIsNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL), WithRetry(3, time.Millisecond))
+
+	if _, err := c.AddOrder("1", "https://example.com/post", 100, nil, nil); err == nil {
+		t.Error("AddOrder succeeded, want an error")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestServiceCacheIsPerKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprintf(w, `[{"service":"1","name":%q}]`, body.Key)
+	}))
+	t.Cleanup(srv.Close)
+	c := New("a", WithEndpoint(srv.URL), WithServiceCache(time.Hour))
+
+	for _, key := range []string{"a", "b", "a"} {
+		services, err := c.ListServicesContext(ContextWithKey(context.Background(), key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != 1 || services[0].Name != key {
+			t.Errorf("services for key %q = %+v", key, services)
+		}
+	}
+}
+
+func TestStreamServicesRateLimiterDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"service":"1","name":"Likes"}]`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New("test", WithEndpoint(srv.URL), WithRateLimit(0.01))
+
+	// The first stream takes the only token; the next is 100 seconds away.
+	services, errc := c.StreamServices(context.Background())
+	for range services {
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	services, errc = c.StreamServices(ctx)
+	for range services {
+	}
+	checkAborted(t, <-errc, context.DeadlineExceeded, time.Since(start))
+}
@@ -0,0 +1,21 @@
+package jap
+
+import "context"
+
+// keyContextKey is the context key for the API key set by ContextWithKey.
+type keyContextKey struct{}
+
+// ContextWithKey returns a copy of ctx that makes calls using it act on behalf
+// of the account with the given API key instead of the key passed to New. This
+// lets a single client serve several accounts.
+func ContextWithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyContextKey{}, key)
+}
+
+// keyFor returns the API key to use for a call with the given context.
+func (c *JAPClient) keyFor(ctx context.Context) string {
+	if key, ok := ctx.Value(keyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	return c.key
+}
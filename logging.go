@@ -0,0 +1,5 @@
+package jap
+
+// Logger is called after each request with the request body, with the API key
+// redacted, the response body, and the error the request failed with, if any.
+type Logger func(req, resp []byte, err error)
@@ -0,0 +1,23 @@
+package jap
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Observer receives metrics about requests, e.g. to export them to Prometheus.
+type Observer interface {
+	// ObserveRequest is called after each call with the action, such as "add"
+	// or "status", how long the call took including retries, and the error it
+	// failed with, if any.
+	ObserveRequest(action string, duration time.Duration, err error)
+}
+
+// requestAction returns the action of a JSON request body.
+func requestAction(body []byte) string {
+	var request struct {
+		Action string `json:"action"`
+	}
+	json.Unmarshal(body, &request)
+	return request.Action
+}
@@ -0,0 +1,59 @@
+package jap
+
+import (
+	"context"
+	"fmt"
+)
+
+// NamedService gives a service ID a readable name, so applications can define
+// the services they use once instead of scattering IDs through their code:
+//
+//	var InstagramLikes = jap.NamedService{Name: "instagram_likes", ID: "1234"}
+//
+//	orderID, err := client.AddNamedOrder(InstagramLikes, link, 100)
+type NamedService struct {
+	Name string
+	ID   string
+}
+
+// RedditUpvoteService is the service ordered by RedditUpvote.
+var RedditUpvoteService = NamedService{Name: "reddit_upvote", ID: "6228"}
+
+// AddNamedOrder adds an order for the given named service and returns the
+// order ID as a string.
+func (c *JAPClient) AddNamedOrder(service NamedService, link string, quantity int) (string, error) {
+	return c.AddNamedOrderContext(context.Background(), service, link, quantity)
+}
+
+// AddNamedOrderContext is like AddNamedOrder but uses the given context.
+func (c *JAPClient) AddNamedOrderContext(ctx context.Context, service NamedService, link string, quantity int) (string, error) {
+	return c.AddOrderContext(ctx, service.ID, link, quantity, nil, nil)
+}
+
+// RegisterService registers alias as a name for the service with the given
+// service ID, for use with OrderByAlias. Registering an alias again replaces
+// it. The name of RedditUpvoteService is registered by default. It is safe to
+// call concurrently with OrderByAlias.
+func (c *JAPClient) RegisterService(alias, serviceID string) {
+	c.aliasesMu.Lock()
+	defer c.aliasesMu.Unlock()
+
+	if c.aliases == nil {
+		c.aliases = make(map[string]string)
+	}
+	c.aliases[alias] = serviceID
+}
+
+// OrderByAlias adds an order for the service registered under alias with
+// RegisterService and returns the order ID as a string. It returns an error
+// wrapping ErrUnknownAlias if no service is registered under alias.
+func (c *JAPClient) OrderByAlias(ctx context.Context, alias, link string, quantity int) (string, error) {
+	c.aliasesMu.RLock()
+	serviceID, ok := c.aliases[alias]
+	c.aliasesMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlias, alias)
+	}
+
+	return c.AddNamedOrderContext(ctx, NamedService{Name: alias, ID: serviceID}, link, quantity)
+}
@@ -0,0 +1,276 @@
+package jap
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultHTTPClient is shared by all clients that don't supply their own, so
+// connections are pooled across them.
+var defaultHTTPClient = &http.Client{
+	Timeout: 60 * time.Second,
+}
+
+// Option configures a JAPClient.
+type Option func(*JAPClient)
+
+// WithHTTPClient sets the HTTP client used to perform requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *JAPClient) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithEndpoint sets the API endpoint, e.g. for a JAP-compatible panel or an
+// httptest.Server in tests. All requests are sent to it.
+func WithEndpoint(endpoint string) Option {
+	return func(c *JAPClient) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithTimeout sets a default deadline of d for each request, applied through
+// its context. If the context passed to a call already has a deadline, the
+// shorter of the two wins. When retries are enabled, each attempt gets its own
+// deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(c *JAPClient) {
+		c.timeout = d
+	}
+}
+
+// WithRetry makes the client retry requests that fail with a network error, a
+// 5xx response or a 429 Too Many Requests response, up to maxAttempts attempts
+// in total. The delay between attempts starts at baseDelay and doubles with
+// every attempt, with jitter, unless a 429 or 503 response carries a
+// Retry-After header, which is honored up to 30 seconds. Requests that fail
+// with another 4xx response or an API error are not retried.
+//
+// Requests that place or change orders, such as AddOrder, CreateRefill and
+// CancelOrders, are only retried after a 429 response, which rejects them, or
+// if the connection to the panel couldn't be established. They are not retried
+// after a timeout or a 5xx response, since the panel may already have acted on
+// them, and retrying could place an order twice and charge for it twice. Check
+// the panel, e.g. with ListOrders where supported, before placing such an
+// order again, and use AddOrderWithKey to keep orders whose ID is known from
+// being placed twice.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *JAPClient) {
+		c.maxAttempts = maxAttempts
+		c.retryDelay = baseDelay
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second, smoothing bursts
+// of calls. Calls wait for their turn or until their context is done. An rps of
+// 0 or less removes the limit.
+func WithRateLimit(rps float64) Option {
+	return func(c *JAPClient) {
+		c.limiter = nil
+		if rps > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(rps), 1)
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request, replacing the
+// default of "jap-api-go/" followed by the library version.
+func WithUserAgent(userAgent string) Option {
+	return func(c *JAPClient) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with each request. It may be given several
+// times. The Content-Type and Accept-Encoding headers are always set by the
+// client and can't be overridden, but other headers such as User-Agent can.
+func WithHeader(key, value string) Option {
+	return func(c *JAPClient) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL, which may be an http,
+// https or socks5 URL. It applies to the client given by WithHTTPClient too,
+// as long as that client uses an *http.Transport. If proxyURL can't be parsed,
+// requests fail with the parse error.
+func WithProxy(proxyURL string) Option {
+	return func(c *JAPClient) {
+		c.proxy = proxyURL
+	}
+}
+
+// withProxy returns a copy of client whose transport uses the given proxy.
+func withProxy(client *http.Client, proxyURL string) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return client
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = func(*http.Request) (*url.URL, error) {
+		return url.Parse(proxyURL)
+	}
+
+	proxied := *client
+	proxied.Transport = transport
+	return &proxied
+}
+
+// WithLogger sets a function called with the raw request and response of each
+// call, for debugging. The API key is redacted from the logged request.
+func WithLogger(logger Logger) Option {
+	return func(c *JAPClient) {
+		c.logger = logger
+	}
+}
+
+// WithServiceCache caches the list of services for ttl, so ListServices and the
+// helpers built on it, such as GetService and SearchServices, don't call the
+// API every time. Services are cached separately for each API key, including
+// keys set with ContextWithKey. Use RefreshServices to reload the cache early.
+func WithServiceCache(ttl time.Duration) Option {
+	return func(c *JAPClient) {
+		c.serviceCache = &serviceCache{ttl: ttl}
+	}
+}
+
+// WithMetrics reports the action, duration and outcome of each call to o.
+func WithMetrics(o Observer) Option {
+	return func(c *JAPClient) {
+		c.observer = o
+	}
+}
+
+// WithTracer starts a span with t around each call, named after the action,
+// e.g. "jap.add", with the service and order IDs of the request as attributes.
+func WithTracer(t Tracer) Option {
+	return func(c *JAPClient) {
+		c.tracer = t
+	}
+}
+
+// WithIdempotencyStore sets the store AddOrderWithKey records idempotency keys
+// in, replacing the default in-memory store.
+func WithIdempotencyStore(store IdempotencyStore) Option {
+	return func(c *JAPClient) {
+		if store != nil {
+			c.idempotencyStore = store
+		}
+	}
+}
+
+// WithCircuitBreaker makes calls fail fast with ErrCircuitOpen after
+// failureThreshold consecutive calls failed with a network error, a non-JSON
+// response or a 5xx response. After cooldown, a single call is let through to
+// probe the API; if it succeeds, calls proceed normally again, otherwise the
+// breaker stays open for another cooldown.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(c *JAPClient) {
+		c.breaker = &circuitBreaker{threshold: failureThreshold, cooldown: cooldown}
+	}
+}
+
+// WithEncoding sets the format request parameters are sent in. The default is
+// EncodingJSON; use EncodingForm for panels, including the JAP API itself, that
+// reject JSON bodies, or EncodingQuery for panels that only read the query
+// string.
+func WithEncoding(encoding Encoding) Option {
+	return func(c *JAPClient) {
+		c.encoding = encoding
+	}
+}
+
+// WithPanel configures the client for the given panel, setting its endpoint,
+// encoding and, if given, status batch size. Options given after WithPanel
+// override its settings.
+func WithPanel(panel PanelProfile) Option {
+	return func(c *JAPClient) {
+		c.endpoint = panel.Endpoint
+		c.encoding = panel.Encoding
+		if panel.StatusBatchSize > 0 {
+			c.statusBatchSize = panel.StatusBatchSize
+		}
+	}
+}
+
+// WithCurrencyConverter sets a converter applied to the amounts returned by
+// the ChargeFloat and BalanceFloat methods of results from the client, and so
+// by GetBalance, to normalize them to a single currency. The Currency fields of
+// the results still hold the currency reported by the API. Amounts are left
+// untouched if no converter is set.
+func WithCurrencyConverter(convert CurrencyConverter) Option {
+	return func(c *JAPClient) {
+		c.convert = nil
+		if convert != nil {
+			c.convert = &convert
+		}
+	}
+}
+
+// WithBaseContext sets a context that bounds the long-running helpers
+// WaitForOrder, WaitForOrderWithStrategy, WaitForOrders and WatchBalance, so
+// they can all be stopped at shutdown by canceling it. These helpers stop as
+// soon as either the base context or the context passed to them is done,
+// whichever comes first; values are only taken from the context passed to
+// them. Other calls use only the context passed to them.
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *JAPClient) {
+		c.baseCtx = ctx
+	}
+}
+
+// WithRequestSigner sets a signer whose header is added to every request. The
+// signer is given the request body exactly as sent, in the encoding set by
+// WithEncoding with its fields sorted by name, or the query string for
+// EncodingQuery. Its header takes precedence over those set with WithHeader.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(c *JAPClient) {
+		c.signer = signer
+	}
+}
+
+// WithStrictDecoding makes the client reject responses with fields it doesn't
+// model, returning the decoding error, to catch schema drift early in
+// development. Types that decode panel variations themselves, such as Service
+// and OrderStatus, which keeps unknown fields in Extra, aren't checked field by
+// field. Decoding is lenient by default.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *JAPClient) {
+		c.strictDecoding = strict
+	}
+}
+
+// WithStatusBatchSize sets the maximum number of order IDs
+// GetMultipleOrderStatus sends in a single request. Larger lists are split
+// into several requests and the results merged. A size of 0 or less sends all
+// IDs in one request.
+func WithStatusBatchSize(size int) Option {
+	return func(c *JAPClient) {
+		c.statusBatchSize = size
+	}
+}
+
+// WithDryRun enables or disables dry-run mode. In dry-run mode, calls that
+// place or change orders, such as AddOrder, CreateRefill and CancelOrders,
+// don't call the API but succeed with an order, refill or cancel ID of 0, and
+// the request that would have been sent is passed to the logger set by
+// WithLogger. Calls that only read data, such as GetUserBalance and
+// ListServices, still call the API.
+func WithDryRun(dryRun bool) Option {
+	return func(c *JAPClient) {
+		c.dryRun = dryRun
+	}
+}
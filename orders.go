@@ -0,0 +1,326 @@
+package jap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OrderParams holds the parameters of an order. Only Service is required; which
+// other fields are needed depends on the type of the service, and fields left
+// at their zero value are not sent.
+type OrderParams struct {
+	Service  string
+	Link     string
+	Quantity int
+
+	// Runs and Interval enable drip-feed: the order is split into Runs runs,
+	// Interval minutes apart.
+	Runs     *int
+	Interval *int
+
+	// Comments, Usernames and Hashtags are sent one entry per line.
+	Comments  []string
+	Usernames []string
+	Hashtags  []string
+
+	Username     string
+	Media        string
+	AnswerNumber int
+
+	// Min, Max, Posts, Delay and Expiry are used by Subscriptions services.
+	Min    int
+	Max    int
+	Posts  int
+	Delay  *int
+	Expiry *string
+}
+
+// AddOrderWithParams adds an order with the given parameters and returns the
+// order ID as a string. It supports every order type; the typed helpers such as
+// AddPollOrder are wrappers around it.
+func (c *JAPClient) AddOrderWithParams(params OrderParams) (string, error) {
+	return c.AddOrderWithParamsContext(context.Background(), params)
+}
+
+// AddOrderWithParamsContext is like AddOrderWithParams but uses the given context.
+func (c *JAPClient) AddOrderWithParamsContext(ctx context.Context, params OrderParams) (string, error) {
+	response, err := c.placeOrder(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return response.OrderID, nil
+}
+
+// placeOrder validates params, adds the order and returns the API's response.
+func (c *JAPClient) placeOrder(ctx context.Context, params OrderParams) (AddOrderResponse, error) {
+	if params.Service == "" {
+		return AddOrderResponse{}, ErrMissingService
+	}
+	if (params.Runs == nil) != (params.Interval == nil) {
+		return AddOrderResponse{}, ErrIncompleteDripFeed
+	}
+	if params.Link != "" {
+		if err := validateLink(params.Link); err != nil {
+			return AddOrderResponse{}, err
+		}
+	}
+
+	orderRequest := struct {
+		Key          string  `json:"key"`
+		Action       string  `json:"action"`
+		Service      string  `json:"service"`
+		Link         string  `json:"link,omitempty"`
+		Quantity     int     `json:"quantity,omitempty"`
+		Runs         *int    `json:"runs,omitempty"`
+		Interval     *int    `json:"interval,omitempty"`
+		Comments     string  `json:"comments,omitempty"`
+		Usernames    string  `json:"usernames,omitempty"`
+		Hashtags     string  `json:"hashtags,omitempty"`
+		Username     string  `json:"username,omitempty"`
+		Media        string  `json:"media,omitempty"`
+		AnswerNumber int     `json:"answer_number,omitempty"`
+		Min          int     `json:"min,omitempty"`
+		Max          int     `json:"max,omitempty"`
+		Posts        int     `json:"posts,omitempty"`
+		Delay        *int    `json:"delay,omitempty"`
+		Expiry       *string `json:"expiry,omitempty"`
+	}{
+		Key:          c.keyFor(ctx),
+		Action:       "add",
+		Service:      params.Service,
+		Link:         params.Link,
+		Quantity:     params.Quantity,
+		Runs:         params.Runs,
+		Interval:     params.Interval,
+		Comments:     strings.Join(params.Comments, "\n"),
+		Usernames:    strings.Join(params.Usernames, "\n"),
+		Hashtags:     strings.Join(params.Hashtags, "\n"),
+		Username:     params.Username,
+		Media:        params.Media,
+		AnswerNumber: params.AnswerNumber,
+		Min:          params.Min,
+		Max:          params.Max,
+		Posts:        params.Posts,
+		Delay:        params.Delay,
+		Expiry:       params.Expiry,
+	}
+
+	return c.addOrder(ctx, orderRequest)
+}
+
+// validateLink returns an error wrapping ErrInvalidLink unless link is an http
+// or https URL. A missing scheme is accepted, e.g. "instagram.com/p/abc".
+func validateLink(link string) error {
+	if link == "" || strings.ContainsAny(link, " \t\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidLink, link)
+	}
+
+	withScheme := link
+	if !strings.Contains(link, "://") {
+		withScheme = "https://" + link
+	}
+	u, err := url.Parse(withScheme)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Hostname(), ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidLink, link)
+	}
+	return nil
+}
+
+// AddCustomCommentsOrder adds an order for a Custom Comments service, posting
+// one comment per entry in comments, and returns the order ID as a string.
+func (c *JAPClient) AddCustomCommentsOrder(service, link string, comments []string) (string, error) {
+	return c.AddCustomCommentsOrderContext(context.Background(), service, link, comments)
+}
+
+// AddCustomCommentsOrderContext is like AddCustomCommentsOrder but uses the given context.
+func (c *JAPClient) AddCustomCommentsOrderContext(ctx context.Context, service, link string, comments []string) (string, error) {
+	if len(comments) == 0 {
+		return "", ErrNoComments
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Link:     link,
+		Comments: comments,
+	})
+}
+
+// AddMentionsOrder adds an order for a Mentions Custom List service, mentioning
+// each of the given usernames, and returns the order ID as a string. Usernames
+// may be given with or without a leading @; it is stripped before sending.
+func (c *JAPClient) AddMentionsOrder(service, link string, usernames []string) (string, error) {
+	return c.AddMentionsOrderContext(context.Background(), service, link, usernames)
+}
+
+// AddMentionsOrderContext is like AddMentionsOrder but uses the given context.
+func (c *JAPClient) AddMentionsOrderContext(ctx context.Context, service, link string, usernames []string) (string, error) {
+	if len(usernames) == 0 {
+		return "", ErrNoUsernames
+	}
+
+	stripped := make([]string, len(usernames))
+	for i, username := range usernames {
+		stripped[i] = strings.TrimPrefix(username, "@")
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:   service,
+		Link:      link,
+		Usernames: stripped,
+	})
+}
+
+// AddMentionsHashtagOrder adds an order for a Mentions Hashtag service, which
+// sources the accounts to mention from the given hashtags, and returns the
+// order ID as a string.
+func (c *JAPClient) AddMentionsHashtagOrder(service, link string, quantity int, hashtags []string) (string, error) {
+	return c.AddMentionsHashtagOrderContext(context.Background(), service, link, quantity, hashtags)
+}
+
+// AddMentionsHashtagOrderContext is like AddMentionsHashtagOrder but uses the given context.
+func (c *JAPClient) AddMentionsHashtagOrderContext(ctx context.Context, service, link string, quantity int, hashtags []string) (string, error) {
+	if len(hashtags) == 0 {
+		return "", ErrNoHashtags
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Link:     link,
+		Quantity: quantity,
+		Hashtags: hashtags,
+	})
+}
+
+// AddMentionsUserFollowersOrder adds an order for a Mentions User Followers
+// service, which mentions followers of the given user, and returns the order ID
+// as a string. Surrounding whitespace and a leading @ are stripped from username.
+func (c *JAPClient) AddMentionsUserFollowersOrder(service, link string, quantity int, username string) (string, error) {
+	return c.AddMentionsUserFollowersOrderContext(context.Background(), service, link, quantity, username)
+}
+
+// AddMentionsUserFollowersOrderContext is like AddMentionsUserFollowersOrder but uses the given context.
+func (c *JAPClient) AddMentionsUserFollowersOrderContext(ctx context.Context, service, link string, quantity int, username string) (string, error) {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	if username == "" {
+		return "", ErrNoUsernames
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Link:     link,
+		Quantity: quantity,
+		Username: username,
+	})
+}
+
+// AddMentionsMediaLikersOrder adds an order for a Mentions Media Likers service,
+// which mentions users who liked the post at the media URL, and returns the
+// order ID as a string.
+func (c *JAPClient) AddMentionsMediaLikersOrder(service, link string, quantity int, media string) (string, error) {
+	return c.AddMentionsMediaLikersOrderContext(context.Background(), service, link, quantity, media)
+}
+
+// AddMentionsMediaLikersOrderContext is like AddMentionsMediaLikersOrder but uses the given context.
+func (c *JAPClient) AddMentionsMediaLikersOrderContext(ctx context.Context, service, link string, quantity int, media string) (string, error) {
+	u, err := url.ParseRequestURI(media)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrInvalidMedia
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Link:     link,
+		Quantity: quantity,
+		Media:    media,
+	})
+}
+
+// AddPollOrder adds an order for a Poll service, voting for the answer with the
+// given 1-based answer number, and returns the order ID as a string.
+func (c *JAPClient) AddPollOrder(service, link string, quantity, answerNumber int) (string, error) {
+	return c.AddPollOrderContext(context.Background(), service, link, quantity, answerNumber)
+}
+
+// AddPollOrderContext is like AddPollOrder but uses the given context.
+func (c *JAPClient) AddPollOrderContext(ctx context.Context, service, link string, quantity, answerNumber int) (string, error) {
+	if answerNumber < 1 {
+		return "", ErrInvalidAnswerNumber
+	}
+
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:      service,
+		Link:         link,
+		Quantity:     quantity,
+		AnswerNumber: answerNumber,
+	})
+}
+
+// AddSubscriptionOrder adds an order for a Subscriptions service, which orders
+// between min and max of the service for each of the next posts posts of the
+// given user, with delay minutes between a post and its order. The optional
+// expiry date is formatted as d/m/Y. It returns the order ID as a string.
+func (c *JAPClient) AddSubscriptionOrder(service, username string, min, max, posts, delay int, expiry *string) (string, error) {
+	return c.AddSubscriptionOrderContext(context.Background(), service, username, min, max, posts, delay, expiry)
+}
+
+// AddSubscriptionOrderContext is like AddSubscriptionOrder but uses the given context.
+func (c *JAPClient) AddSubscriptionOrderContext(ctx context.Context, service, username string, min, max, posts, delay int, expiry *string) (string, error) {
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Username: username,
+		Min:      min,
+		Max:      max,
+		Posts:    posts,
+		Delay:    &delay,
+		Expiry:   expiry,
+	})
+}
+
+// AddCommentLikesOrder adds an order for a Comment Likes service and returns the
+// order ID as a string. The link is the URL of the post, and username is the
+// author of the comment on that post to like.
+func (c *JAPClient) AddCommentLikesOrder(service, link string, quantity int, username string) (string, error) {
+	return c.AddCommentLikesOrderContext(context.Background(), service, link, quantity, username)
+}
+
+// AddCommentLikesOrderContext is like AddCommentLikesOrder but uses the given context.
+func (c *JAPClient) AddCommentLikesOrderContext(ctx context.Context, service, link string, quantity int, username string) (string, error) {
+	return c.AddOrderWithParamsContext(ctx, OrderParams{
+		Service:  service,
+		Link:     link,
+		Quantity: quantity,
+		Username: username,
+	})
+}
+
+// AddCustomCommentsPackageOrder adds an order for a Custom Comments Package
+// service, posting one comment per entry in comments, and returns the order ID
+// as a string. Unlike AddCustomCommentsOrder, the price is per package rather
+// than per comment, but the request is the same.
+func (c *JAPClient) AddCustomCommentsPackageOrder(service, link string, comments []string) (string, error) {
+	return c.AddCustomCommentsPackageOrderContext(context.Background(), service, link, comments)
+}
+
+// AddCustomCommentsPackageOrderContext is like AddCustomCommentsPackageOrder but uses the given context.
+func (c *JAPClient) AddCustomCommentsPackageOrderContext(ctx context.Context, service, link string, comments []string) (string, error) {
+	return c.AddCustomCommentsOrderContext(ctx, service, link, comments)
+}
+
+// AddDripFeedOrder adds a drip-feed order, which delivers quantity in each of
+// runs runs, intervalMinutes minutes apart, and returns the order ID as a
+// string. It returns ErrInvalidDripFeed if runs is below 1 or intervalMinutes
+// isn't positive.
+func (c *JAPClient) AddDripFeedOrder(service, link string, quantity, runs, intervalMinutes int) (string, error) {
+	return c.AddDripFeedOrderContext(context.Background(), service, link, quantity, runs, intervalMinutes)
+}
+
+// AddDripFeedOrderContext is like AddDripFeedOrder but uses the given context.
+func (c *JAPClient) AddDripFeedOrderContext(ctx context.Context, service, link string, quantity, runs, intervalMinutes int) (string, error) {
+	if runs < 1 || intervalMinutes <= 0 {
+		return "", ErrInvalidDripFeed
+	}
+
+	return c.AddOrderContext(ctx, service, link, quantity, &runs, &intervalMinutes)
+}
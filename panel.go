@@ -0,0 +1,41 @@
+package jap
+
+// PanelProfile describes how to talk to a JAP-compatible panel, so a client
+// can be configured for it in one go with WithPanel:
+//
+//	client := jap.New(key, jap.WithPanel(jap.JAPPanel))
+//
+// Profiles for other panels can be defined the same way:
+//
+//	var MyPanel = jap.PanelProfile{
+//		Name:     "mypanel",
+//		Endpoint: "https://mypanel.example/api/v2",
+//		Encoding: jap.EncodingForm,
+//	}
+type PanelProfile struct {
+	// Name identifies the panel.
+	Name string
+	// Endpoint is the URL of the panel's API.
+	Endpoint string
+	// Encoding is the format the panel accepts request parameters in.
+	Encoding Encoding
+	// StatusBatchSize is the maximum number of order IDs the panel accepts in
+	// a single status request, or 0 to keep the client's default.
+	StatusBatchSize int
+}
+
+// JAPPanel is the profile of JustAnotherPanel itself. Unlike a client created
+// by New without options, which sends JSON to the same endpoint, it uses
+// EncodingForm.
+var JAPPanel = PanelProfile{
+	Name:     "justanotherpanel",
+	Endpoint: "https://justanotherpanel.com/api/v2",
+	Encoding: EncodingForm,
+}
+
+// PeakerrPanel is the profile of Peakerr, a JAP-compatible panel.
+var PeakerrPanel = PanelProfile{
+	Name:     "peakerr",
+	Endpoint: "https://peakerr.com/api/v2",
+	Encoding: EncodingForm,
+}
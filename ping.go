@@ -0,0 +1,52 @@
+package jap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Ping checks that the endpoint is reachable and accepts the API key by
+// requesting the balance, the cheapest call the API offers. It returns nil on
+// success, an error wrapping ErrUnauthorized if the key was rejected, or an
+// error wrapping ErrUnreachable if the endpoint couldn't be reached. Other
+// failures, such as an HTTP error status, are returned unchanged.
+func (c *JAPClient) Ping(ctx context.Context) error {
+	_, err := c.GetUserBalanceContext(ctx)
+	switch {
+	case err == nil:
+		return nil
+	case isAuthError(err):
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	case isNetworkError(err):
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	return err
+}
+
+// isAuthError reports whether err means the API rejected the API key.
+func isAuthError(err error) bool {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden ||
+			strings.Contains(strings.ToLower(apiErr.Message), "key")
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// isNetworkError reports whether err means no response was received, e.g.
+// because the host couldn't be resolved or the connection was refused.
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,73 @@
+package jap
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redacted replaces the API key wherever it could be printed.
+const redacted = "REDACTED"
+
+// redactKey returns a copy of the JSON request body with the API key replaced.
+// Bodies that aren't JSON objects are returned as is.
+func redactKey(body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+	if _, ok := fields["key"]; !ok {
+		return body
+	}
+
+	fields["key"], _ = json.Marshal(redacted)
+	redactedBody, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+// redactedError is an error whose message has the API key replaced. It unwraps
+// to the original error, so errors.Is and errors.As still work.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with every occurrence of the given keys in its
+// message replaced.
+func redactError(err error, keys ...string) error {
+	if err == nil {
+		return err
+	}
+
+	msg := err.Error()
+	redactedMsg := msg
+	for _, key := range keys {
+		if key != "" {
+			redactedMsg = strings.ReplaceAll(redactedMsg, key, redacted)
+		}
+	}
+	if redactedMsg == msg {
+		return err
+	}
+	return redactedError{err: err, msg: redactedMsg}
+}
+
+// String describes the client without revealing its API key.
+func (c *JAPClient) String() string {
+	return "jap.JAPClient{endpoint: " + c.endpoint + ", key: " + redacted + "}"
+}
+
+// GoString is like String, so the key isn't revealed by the %#v verb either.
+func (c *JAPClient) GoString() string {
+	return c.String()
+}
@@ -0,0 +1,230 @@
+package jap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CreateRefill requests a refill for the order with the given order ID and
+// returns the refill ID. If the order's service doesn't support refills, the
+// returned error matches ErrRefillNotSupported.
+func (c *JAPClient) CreateRefill(orderID string) (string, error) {
+	return c.CreateRefillContext(context.Background(), orderID)
+}
+
+// CreateRefillContext is like CreateRefill but uses the given context.
+func (c *JAPClient) CreateRefillContext(ctx context.Context, orderID string) (string, error) {
+	body := struct {
+		Key    string `json:"key"`
+		Action string `json:"action"`
+		Order  string `json:"order"`
+	}{
+		Key:    c.keyFor(ctx),
+		Action: "refill",
+		Order:  orderID,
+	}
+	type createRefillResponse struct {
+		Refill flexString `json:"refill"`
+	}
+	response, err := do[createRefillResponse](ctx, c, body)
+	if err != nil {
+		var apiErr APIError
+		if errors.As(err, &apiErr) && isRefillNotSupported(apiErr.Message) {
+			return "", fmt.Errorf("%w: %w", ErrRefillNotSupported, apiErr)
+		}
+		return "", err
+	}
+
+	return string(response.Refill), nil
+}
+
+// RefillResult is the result of requesting a refill for one order in a batch.
+type RefillResult struct {
+	Order  string
+	Refill string
+	// Error is set instead of Refill when the refill could not be created.
+	Error string
+}
+
+// UnmarshalJSON decodes an {"order":1,"refill":1} object, where refill may
+// instead be an {"error":"..."} object.
+func (r *RefillResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Order  flexString      `json:"order"`
+		Refill json.RawMessage `json:"refill"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	refill, errMsg, err := decodeValueOrError(raw.Refill)
+	if err != nil {
+		return err
+	}
+
+	*r = RefillResult{
+		Order:  string(raw.Order),
+		Refill: refill,
+		Error:  errMsg,
+	}
+	return nil
+}
+
+// CreateMultipleRefill requests refills for several orders in a single request.
+// Orders that could not be refilled are reported through the Error field of
+// their result rather than failing the whole call.
+func (c *JAPClient) CreateMultipleRefill(orderIDs []string) ([]RefillResult, error) {
+	return c.CreateMultipleRefillContext(context.Background(), orderIDs)
+}
+
+// CreateMultipleRefillContext is like CreateMultipleRefill but uses the given context.
+func (c *JAPClient) CreateMultipleRefillContext(ctx context.Context, orderIDs []string) ([]RefillResult, error) {
+	if len(orderIDs) == 0 {
+		return []RefillResult{}, nil
+	}
+
+	body := struct {
+		Key    string `json:"key"`
+		Action string `json:"action"`
+		Orders string `json:"orders"`
+	}{
+		Key:    c.keyFor(ctx),
+		Action: "refill",
+		Orders: strings.Join(orderIDs, ","),
+	}
+	return do[[]RefillResult](ctx, c, body)
+}
+
+// GetRefillStatus checks the status of the refill with the given refill ID and
+// returns the raw status, e.g. "Completed", "In progress" or "Rejected".
+func (c *JAPClient) GetRefillStatus(refillID string) (string, error) {
+	return c.GetRefillStatusContext(context.Background(), refillID)
+}
+
+// GetRefillStatusContext is like GetRefillStatus but uses the given context.
+func (c *JAPClient) GetRefillStatusContext(ctx context.Context, refillID string) (string, error) {
+	body := struct {
+		Key    string `json:"key"`
+		Action string `json:"action"`
+		Refill string `json:"refill"`
+	}{
+		Key:    c.keyFor(ctx),
+		Action: "refill_status",
+		Refill: refillID,
+	}
+	type refillStatusResponse struct {
+		Status string `json:"status"`
+	}
+	response, err := do[refillStatusResponse](ctx, c, body)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Status, nil
+}
+
+// RefillStatusResult is the status of one refill in a batch.
+type RefillStatusResult struct {
+	Refill string
+	Status string
+	// Error is set instead of Status when the refill could not be looked up.
+	Error string
+}
+
+// UnmarshalJSON decodes a {"refill":1,"status":"Completed"} object, where status
+// may instead be an {"error":"..."} object.
+func (r *RefillStatusResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Refill flexString      `json:"refill"`
+		Status json.RawMessage `json:"status"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	status, errMsg, err := decodeValueOrError(raw.Status)
+	if err != nil {
+		return err
+	}
+
+	*r = RefillStatusResult{
+		Refill: string(raw.Refill),
+		Status: status,
+		Error:  errMsg,
+	}
+	return nil
+}
+
+// GetMultipleRefillStatus checks the status of several refills in a single
+// request. Refills that could not be looked up are reported through the Error
+// field of their result rather than failing the whole call.
+func (c *JAPClient) GetMultipleRefillStatus(refillIDs []string) ([]RefillStatusResult, error) {
+	return c.GetMultipleRefillStatusContext(context.Background(), refillIDs)
+}
+
+// GetMultipleRefillStatusContext is like GetMultipleRefillStatus but uses the given context.
+func (c *JAPClient) GetMultipleRefillStatusContext(ctx context.Context, refillIDs []string) ([]RefillStatusResult, error) {
+	if len(refillIDs) == 0 {
+		return []RefillStatusResult{}, nil
+	}
+
+	body := struct {
+		Key     string `json:"key"`
+		Action  string `json:"action"`
+		Refills string `json:"refills"`
+	}{
+		Key:     c.keyFor(ctx),
+		Action:  "refill_status",
+		Refills: strings.Join(refillIDs, ","),
+	}
+	return do[[]RefillStatusResult](ctx, c, body)
+}
+
+// isRefillNotSupported reports whether an API error message indicates that
+// refills are unavailable for an order.
+func isRefillNotSupported(message string) bool {
+	message = strings.ToLower(message)
+	if !strings.Contains(message, "refill") {
+		return false
+	}
+	for _, s := range []string{"not", "disabled", "unavailable"} {
+		if strings.Contains(message, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeValueOrError decodes a batch result value that is either a string or
+// number, such as an ID or status, or an {"error":"..."} object. It is lenient,
+// so one odd entry doesn't fail a whole batch: values of other types are
+// returned as their JSON text, and objects without an error as errors with
+// their JSON text.
+func decodeValueOrError(data json.RawMessage) (value, errMsg string, err error) {
+	if len(data) == 0 || string(data) == "null" {
+		return "", "", nil
+	}
+
+	if data[0] == '{' {
+		var e struct {
+			Error json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(data, &e); err != nil {
+			return "", "", err
+		}
+		if len(e.Error) == 0 || string(e.Error) == "null" {
+			return "", string(data), nil
+		}
+		return "", errorMessage(e.Error), nil
+	}
+
+	var v flexString
+	if err := json.Unmarshal(data, &v); err != nil {
+		// Report values of other types, such as booleans, as their JSON text.
+		return string(data), "", nil
+	}
+	return string(v), "", nil
+}
@@ -0,0 +1,36 @@
+package jap
+
+import (
+	"context"
+	"time"
+)
+
+// ResponseInfo describes how a call went, for monitoring.
+type ResponseInfo struct {
+	// Duration is how long the call took, including retries.
+	Duration time.Duration
+	// Attempts is the number of HTTP requests made, or 0 if none was, e.g. in
+	// dry-run mode or while the circuit breaker is open.
+	Attempts int
+	// StatusCode is the HTTP status of the last response, or 0 if there was
+	// none.
+	StatusCode int
+}
+
+// responseInfoContextKey is the context key for the ResponseInfo set by
+// ContextWithResponseInfo.
+type responseInfoContextKey struct{}
+
+// ContextWithResponseInfo returns a copy of ctx that makes a call using it
+// record its ResponseInfo into info. Use a separate context and info for each
+// call.
+func ContextWithResponseInfo(ctx context.Context, info *ResponseInfo) context.Context {
+	return context.WithValue(ctx, responseInfoContextKey{}, info)
+}
+
+// responseInfoFrom returns the ResponseInfo set by ContextWithResponseInfo, or
+// nil if there is none.
+func responseInfoFrom(ctx context.Context) *ResponseInfo {
+	info, _ := ctx.Value(responseInfoContextKey{}).(*ResponseInfo)
+	return info
+}
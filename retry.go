@@ -0,0 +1,130 @@
+package jap
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryDelay caps the delay between two attempts.
+const maxRetryDelay = 30 * time.Second
+
+// nonIdempotentActions are the actions that place or change orders. Repeating
+// one after the panel acted on it could place an order twice, so they are only
+// retried when the panel certainly didn't: the request never reached it, or it
+// was rejected with a 429.
+var nonIdempotentActions = map[string]bool{
+	"add":    true,
+	"refill": true,
+	"cancel": true,
+}
+
+// sendWithRetry sends the request, retrying transient failures and 429
+// responses as configured by WithRetry. Requests for non-idempotent actions are
+// only retried after a 429 or if they failed to connect. A Retry-After header
+// on a 429 or 503 response sets the delay before the next attempt; otherwise
+// the delay is given by backoff.
+func (c *JAPClient) sendWithRetry(ctx context.Context, bodyJSON []byte) ([]byte, int, error) {
+	info := responseInfoFrom(ctx)
+	idempotent := !nonIdempotentActions[requestAction(bodyJSON)]
+	for attempt := 1; ; attempt++ {
+		responseBody, statusCode, header, err := c.send(ctx, bodyJSON)
+		if info != nil {
+			info.Attempts = attempt
+		}
+		rateLimited := statusCode == http.StatusTooManyRequests && ctx.Err() == nil
+		retryable := rateLimited || isTransient(ctx, statusCode, err)
+		if !idempotent {
+			retryable = ctx.Err() == nil && (rateLimited || isDialError(err))
+		}
+		if attempt >= c.maxAttempts || !retryable {
+			return responseBody, statusCode, err
+		}
+
+		delay := c.backoff(attempt)
+		if d, ok := retryAfter(header, time.Now()); ok && (rateLimited || statusCode == http.StatusServiceUnavailable) {
+			delay = d
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+// isTransient reports whether a request that failed with the given status code
+// or error failed transiently, and is worth retrying. Network errors, non-JSON
+// responses and 5xx responses are considered transient; 4xx responses and API
+// errors are not.
+func isTransient(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// isDialError reports whether err means the connection to the panel couldn't
+// be established, e.g. because it was refused or the host couldn't be
+// resolved, so the request certainly wasn't received.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// backoff returns the delay before the attempt following the given one. The
+// delay doubles with every attempt, with jitter of up to half of it.
+func (c *JAPClient) backoff(attempt int) time.Duration {
+	if c.retryDelay <= 0 {
+		return 0
+	}
+	d := c.retryDelay << (attempt - 1)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter parses the Retry-After header, given either as a number of
+// seconds or as an HTTP date, into the delay it asks for from now, capped at
+// maxRetryDelay. It reports false if the header is absent or malformed.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		seconds = min(seconds, int(maxRetryDelay/time.Second))
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return min(max(t.Sub(now), 0), maxRetryDelay), true
+}
+
+// sleepContext sleeps for d or until ctx is done, whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package jap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RateFloat returns the rate of the service, the price per 1000, as a float64.
+func (s Service) RateFloat() (float64, error) {
+	return strconv.ParseFloat(s.Rate, 64)
+}
+
+// MinInt returns the minimum quantity of the service as an int.
+func (s Service) MinInt() (int, error) {
+	return strconv.Atoi(s.Min)
+}
+
+// MaxInt returns the maximum quantity of the service as an int.
+func (s Service) MaxInt() (int, error) {
+	return strconv.Atoi(s.Max)
+}
+
+// CheckQuantity returns an error wrapping ErrQuantityOutOfRange if quantity is
+// below the minimum or above the maximum of the service.
+func (s Service) CheckQuantity(quantity int) error {
+	min, err := s.MinInt()
+	if err != nil {
+		return err
+	}
+	max, err := s.MaxInt()
+	if err != nil {
+		return err
+	}
+	if quantity < min || quantity > max {
+		return fmt.Errorf("%w: %d not between %d and %d", ErrQuantityOutOfRange, quantity, min, max)
+	}
+	return nil
+}
+
+// Cost returns the price of ordering quantity of the service. Rates are per 1000,
+// so the cost is rate / 1000 * quantity.
+func (s Service) Cost(quantity int) (float64, error) {
+	rate, err := s.RateFloat()
+	if err != nil {
+		return 0, err
+	}
+	return rate / 1000 * float64(quantity), nil
+}
+
+// SortServicesByRate sorts services in place by their parsed rate, cheapest
+// first if ascending is true. Services whose rate can't be parsed are sorted
+// last either way, and services with equal rates keep their order.
+func SortServicesByRate(services []Service, ascending bool) {
+	sort.SliceStable(services, func(i, j int) bool {
+		ri, erri := services[i].RateFloat()
+		rj, errj := services[j].RateFloat()
+		if erri != nil || errj != nil {
+			return erri == nil && errj != nil
+		}
+		if ascending {
+			return ri < rj
+		}
+		return ri > rj
+	})
+}
+
+// ListCategories retrieves the distinct categories of the services from the
+// API, sorted alphabetically.
+func (c *JAPClient) ListCategories() ([]string, error) {
+	return c.ListCategoriesContext(context.Background())
+}
+
+// ListCategoriesContext is like ListCategories but uses the given context.
+func (c *JAPClient) ListCategoriesContext(ctx context.Context) ([]string, error) {
+	services, err := c.ListServicesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	categories := []string{}
+	for _, s := range services {
+		if !seen[s.Category] {
+			seen[s.Category] = true
+			categories = append(categories, s.Category)
+		}
+	}
+	sort.Strings(categories)
+
+	return categories, nil
+}
+
+// ServicesByCategory retrieves the services in the given category from the API,
+// matching the category case-insensitively. It returns an empty slice if no
+// service is in the category.
+func (c *JAPClient) ServicesByCategory(category string) ([]Service, error) {
+	return c.ServicesByCategoryContext(context.Background(), category)
+}
+
+// ServicesByCategoryContext is like ServicesByCategory but uses the given context.
+func (c *JAPClient) ServicesByCategoryContext(ctx context.Context, category string) ([]Service, error) {
+	return c.FilterServicesContext(ctx, func(s Service) bool {
+		return strings.EqualFold(s.Category, category)
+	})
+}
+
+// SearchServices retrieves the services whose name or category contains query,
+// ignoring case.
+func (c *JAPClient) SearchServices(query string) ([]Service, error) {
+	return c.SearchServicesContext(context.Background(), query)
+}
+
+// SearchServicesContext is like SearchServices but uses the given context.
+func (c *JAPClient) SearchServicesContext(ctx context.Context, query string) ([]Service, error) {
+	query = strings.ToLower(query)
+	return c.FilterServicesContext(ctx, func(s Service) bool {
+		return strings.Contains(strings.ToLower(s.Name), query) ||
+			strings.Contains(strings.ToLower(s.Category), query)
+	})
+}
+
+// GetService retrieves the service with the given service ID from the API. It
+// returns ErrServiceNotFound if there is no such service.
+func (c *JAPClient) GetService(serviceID string) (Service, error) {
+	return c.GetServiceContext(context.Background(), serviceID)
+}
+
+// GetServiceContext is like GetService but uses the given context.
+func (c *JAPClient) GetServiceContext(ctx context.Context, serviceID string) (Service, error) {
+	services, err := c.ListServicesContext(ctx)
+	if err != nil {
+		return Service{}, err
+	}
+
+	for _, s := range services {
+		if s.Service == serviceID {
+			return s, nil
+		}
+	}
+
+	return Service{}, ErrServiceNotFound
+}
+
+// EstimateOrderCost retrieves the service with the given service ID and returns
+// the price of ordering quantity of it. Rates are per 1000, so the cost is
+// rate / 1000 * quantity.
+func (c *JAPClient) EstimateOrderCost(serviceID string, quantity int) (float64, error) {
+	return c.EstimateOrderCostContext(context.Background(), serviceID, quantity)
+}
+
+// EstimateOrderCostContext is like EstimateOrderCost but uses the given context.
+func (c *JAPClient) EstimateOrderCostContext(ctx context.Context, serviceID string, quantity int) (float64, error) {
+	service, err := c.GetServiceContext(ctx, serviceID)
+	if err != nil {
+		return 0, err
+	}
+	return service.Cost(quantity)
+}
+
+// CheapestService returns the service in category, compared ignoring case,
+// that can fulfill quantity at the lowest cost, along with that cost. Services
+// whose minimum and maximum exclude quantity, or whose rate can't be parsed,
+// are skipped. It returns ErrNoEligibleService if no service qualifies.
+func (c *JAPClient) CheapestService(category string, quantity int) (Service, float64, error) {
+	return c.CheapestServiceContext(context.Background(), category, quantity)
+}
+
+// CheapestServiceContext is like CheapestService but uses the given context.
+func (c *JAPClient) CheapestServiceContext(ctx context.Context, category string, quantity int) (Service, float64, error) {
+	services, err := c.ServicesByCategoryContext(ctx, category)
+	if err != nil {
+		return Service{}, 0, err
+	}
+
+	var (
+		cheapest Service
+		minCost  float64
+		found    bool
+	)
+	for _, s := range services {
+		if s.CheckQuantity(quantity) != nil {
+			continue
+		}
+		cost, err := s.Cost(quantity)
+		if err != nil {
+			continue
+		}
+		if !found || cost < minCost {
+			cheapest, minCost, found = s, cost, true
+		}
+	}
+	if !found {
+		return Service{}, 0, fmt.Errorf("%w: category %q, quantity %d", ErrNoEligibleService, category, quantity)
+	}
+	return cheapest, minCost, nil
+}
+
+// RefillableServices retrieves the services that support refills.
+func (c *JAPClient) RefillableServices() ([]Service, error) {
+	return c.RefillableServicesContext(context.Background())
+}
+
+// RefillableServicesContext is like RefillableServices but uses the given context.
+func (c *JAPClient) RefillableServicesContext(ctx context.Context) ([]Service, error) {
+	return c.FilterServicesContext(ctx, func(s Service) bool {
+		return s.Refill
+	})
+}
+
+// CancelableServices retrieves the services that support cancellation.
+func (c *JAPClient) CancelableServices() ([]Service, error) {
+	return c.CancelableServicesContext(context.Background())
+}
+
+// CancelableServicesContext is like CancelableServices but uses the given context.
+func (c *JAPClient) CancelableServicesContext(ctx context.Context) ([]Service, error) {
+	return c.FilterServicesContext(ctx, func(s Service) bool {
+		return s.Cancel
+	})
+}
+
+// FilterServices retrieves the services for which pred returns true, so
+// criteria can be combined freely:
+//
+//	services, err := client.FilterServices(func(s jap.Service) bool {
+//		rate, err := s.RateFloat()
+//		return s.Refill && err == nil && rate < 1 && strings.Contains(s.Category, "Instagram")
+//	})
+//
+// The services are listed with ListServices, so the cache set by
+// WithServiceCache is used if enabled.
+func (c *JAPClient) FilterServices(pred func(Service) bool) ([]Service, error) {
+	return c.FilterServicesContext(context.Background(), pred)
+}
+
+// FilterServicesContext is like FilterServices but uses the given context.
+func (c *JAPClient) FilterServicesContext(ctx context.Context, pred func(Service) bool) ([]Service, error) {
+	services, err := c.ListServicesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []Service{}
+	for _, s := range services {
+		if pred(s) {
+			filtered = append(filtered, s)
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,150 @@
+package jap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ServiceType is the type of a service, which determines the parameters its
+// orders take.
+type ServiceType int
+
+// Service types reported by the API. ServiceTypeUnknown is used for any type
+// this package doesn't recognize.
+const (
+	ServiceTypeUnknown ServiceType = iota
+	ServiceTypeDefault
+	ServiceTypePackage
+	ServiceTypeCustomComments
+	ServiceTypeCustomCommentsPackage
+	ServiceTypeMentionsCustomList
+	ServiceTypeMentionsHashtag
+	ServiceTypeMentionsUserFollowers
+	ServiceTypeMentionsMediaLikers
+	ServiceTypePoll
+	ServiceTypeCommentLikes
+	ServiceTypeSubscriptions
+)
+
+var serviceTypeNames = map[ServiceType]string{
+	ServiceTypeUnknown:               "Unknown",
+	ServiceTypeDefault:               "Default",
+	ServiceTypePackage:               "Package",
+	ServiceTypeCustomComments:        "Custom Comments",
+	ServiceTypeCustomCommentsPackage: "Custom Comments Package",
+	ServiceTypeMentionsCustomList:    "Mentions Custom List",
+	ServiceTypeMentionsHashtag:       "Mentions Hashtag",
+	ServiceTypeMentionsUserFollowers: "Mentions User Followers",
+	ServiceTypeMentionsMediaLikers:   "Mentions Media Likers",
+	ServiceTypePoll:                  "Poll",
+	ServiceTypeCommentLikes:          "Comment Likes",
+	ServiceTypeSubscriptions:         "Subscriptions",
+}
+
+// serviceTypeParams are the parameters required by orders of each service
+// type, besides the service itself.
+var serviceTypeParams = map[ServiceType][]string{
+	ServiceTypeDefault:               {"link", "quantity"},
+	ServiceTypePackage:               {"link"},
+	ServiceTypeCustomComments:        {"link", "comments"},
+	ServiceTypeCustomCommentsPackage: {"link", "comments"},
+	ServiceTypeMentionsCustomList:    {"link", "usernames"},
+	ServiceTypeMentionsHashtag:       {"link", "quantity", "hashtags"},
+	ServiceTypeMentionsUserFollowers: {"link", "quantity", "username"},
+	ServiceTypeMentionsMediaLikers:   {"link", "quantity", "media"},
+	ServiceTypePoll:                  {"link", "quantity", "answer_number"},
+	ServiceTypeCommentLikes:          {"link", "quantity", "username"},
+	ServiceTypeSubscriptions:         {"username", "min", "max", "posts", "delay"},
+}
+
+// ParseServiceType parses a service type as reported by the API, ignoring case.
+// Unknown types are returned as ServiceTypeUnknown.
+func ParseServiceType(s string) ServiceType {
+	s = strings.TrimSpace(s)
+	for t, name := range serviceTypeNames {
+		if t != ServiceTypeUnknown && strings.EqualFold(s, name) {
+			return t
+		}
+	}
+	return ServiceTypeUnknown
+}
+
+func (t ServiceType) String() string {
+	if name, ok := serviceTypeNames[t]; ok {
+		return name
+	}
+	return serviceTypeNames[ServiceTypeUnknown]
+}
+
+// ParsedType returns the type of the service as a ServiceType.
+func (s Service) ParsedType() ServiceType {
+	return ParseServiceType(s.Type)
+}
+
+// RequiredParams returns the names of the parameters, as sent to the API, that
+// orders for the service require besides the service itself. It returns nil if
+// the type of the service is unknown.
+func (s Service) RequiredParams() []string {
+	return append([]string(nil), serviceTypeParams[s.ParsedType()]...)
+}
+
+// hasParam reports whether params sets the parameter with the given name, as
+// returned by RequiredParams.
+func (p OrderParams) hasParam(name string) bool {
+	switch name {
+	case "link":
+		return p.Link != ""
+	case "quantity":
+		return p.Quantity > 0
+	case "comments":
+		return len(p.Comments) > 0
+	case "usernames":
+		return len(p.Usernames) > 0
+	case "hashtags":
+		return len(p.Hashtags) > 0
+	case "username":
+		return p.Username != ""
+	case "media":
+		return p.Media != ""
+	case "answer_number":
+		return p.AnswerNumber > 0
+	case "min":
+		return p.Min > 0
+	case "max":
+		return p.Max > 0
+	case "posts":
+		return p.Posts > 0
+	case "delay":
+		return p.Delay != nil
+	default:
+		return false
+	}
+}
+
+// AddOrderAuto adds an order for the service with the given service ID after
+// checking that params sets every parameter its type requires, and returns the
+// order ID as a string. The service ID overrides params.Service. Missing
+// parameters are reported by an error wrapping ErrMissingParams. Services of
+// unknown type are ordered without checks. The service is looked up with
+// GetService, so the check is free of network calls when WithServiceCache is
+// used.
+func (c *JAPClient) AddOrderAuto(ctx context.Context, serviceID string, params OrderParams) (string, error) {
+	service, err := c.GetServiceContext(ctx, serviceID)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for _, name := range service.RequiredParams() {
+		if !params.hasParam(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w for %s service %s: %s", ErrMissingParams, service.ParsedType(), serviceID, strings.Join(missing, ", "))
+	}
+
+	params.Service = serviceID
+	return c.AddOrderWithParamsContext(ctx, params)
+}
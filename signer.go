@@ -0,0 +1,41 @@
+package jap
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RequestSigner computes a header authenticating a request from its body, for
+// panels that require a signature. For example, an HMAC-SHA256 signer is:
+//
+//	func(body []byte) (string, string, error) {
+//		mac := hmac.New(sha256.New, secret)
+//		mac.Write(body)
+//		return "X-Signature", hex.EncodeToString(mac.Sum(nil)), nil
+//	}
+type RequestSigner func(body []byte) (headerName, headerValue string, err error)
+
+// sign sets the header computed by the signer set with WithRequestSigner on
+// req. The signer is given the body exactly as sent or, for requests without a
+// body such as those of EncodingQuery, the encoded query string.
+func (c *JAPClient) sign(req *http.Request) error {
+	payload := []byte(req.URL.RawQuery)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	name, value, err := c.signer(payload)
+	if err != nil {
+		return fmt.Errorf("jap: signing request: %w", err)
+	}
+	req.Header.Set(name, value)
+	return nil
+}
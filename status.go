@@ -0,0 +1,124 @@
+package jap
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Status is the status of an order.
+type Status int
+
+// Statuses reported by the API. StatusUnknown is used for any status this
+// package doesn't recognize.
+const (
+	StatusUnknown Status = iota
+	StatusPending
+	StatusInProgress
+	StatusProcessing
+	StatusPartial
+	StatusCompleted
+	StatusCanceled
+)
+
+var statusNames = map[Status]string{
+	StatusUnknown:    "Unknown",
+	StatusPending:    "Pending",
+	StatusInProgress: "In progress",
+	StatusProcessing: "Processing",
+	StatusPartial:    "Partial",
+	StatusCompleted:  "Completed",
+	StatusCanceled:   "Canceled",
+}
+
+// ParseStatus parses a status as reported by the API, ignoring case. Unknown
+// statuses are returned as StatusUnknown.
+func ParseStatus(s string) Status {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "pending":
+		return StatusPending
+	case "in progress":
+		return StatusInProgress
+	case "processing":
+		return StatusProcessing
+	case "partial":
+		return StatusPartial
+	case "completed":
+		return StatusCompleted
+	case "canceled", "cancelled":
+		return StatusCanceled
+	default:
+		return StatusUnknown
+	}
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return statusNames[StatusUnknown]
+}
+
+// orderStatusFields are the JSON fields decoded into the fields of
+// OrderStatus; any others are kept in Extra.
+var orderStatusFields = []string{"charge", "start_count", "status", "remains", "currency", "error"}
+
+// UnmarshalJSON decodes an order status, keeping fields OrderStatus has no
+// field for in Extra.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	type orderStatus OrderStatus
+	var known orderStatus
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for _, name := range orderStatusFields {
+		delete(fields, name)
+	}
+	*s = OrderStatus(known)
+	if len(fields) > 0 {
+		s.Extra = fields
+	}
+	return nil
+}
+
+// ParsedStatus returns the status of the order as a Status.
+func (s OrderStatus) ParsedStatus() Status {
+	return ParseStatus(s.Status)
+}
+
+// ChargeFloat returns the charge of the order as a float64. It returns
+// ErrEmptyField if the API didn't report a charge. If the status was returned
+// by a client with a converter set by WithCurrencyConverter, the charge is
+// converted from Currency with it.
+func (s OrderStatus) ChargeFloat() (float64, error) {
+	return parseAmount(s.Charge, s.Currency, s.convert)
+}
+
+// StartCountInt returns the start count of the order as an int. It returns
+// ErrEmptyField if the API didn't report a start count.
+func (s OrderStatus) StartCountInt() (int, error) {
+	if s.StartCount == "" {
+		return 0, ErrEmptyField
+	}
+	return strconv.Atoi(s.StartCount)
+}
+
+// RemainsInt returns the remaining quantity of the order as an int. It returns
+// ErrEmptyField if the API didn't report the remaining quantity.
+func (s OrderStatus) RemainsInt() (int, error) {
+	if s.Remains == "" {
+		return 0, ErrEmptyField
+	}
+	return strconv.Atoi(s.Remains)
+}
+
+// Terminal reports whether the status is final, i.e. the order won't change
+// anymore.
+func (s Status) Terminal() bool {
+	return s == StatusCompleted || s == StatusPartial || s == StatusCanceled
+}
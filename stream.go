@@ -0,0 +1,111 @@
+package jap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// StreamServices retrieves the list of services from the API like ListServices,
+// but decodes the response incrementally and sends each service on the
+// returned channel, so the whole list is never held in memory. The service
+// channel is closed when the list ends or the stream fails; in the latter case
+// the error is sent on the error channel first. Both channels are closed when
+// StreamServices is done. Canceling ctx stops the stream. The service cache,
+// retries, and the logger, metrics and tracing hooks are not used.
+func (c *JAPClient) StreamServices(ctx context.Context) (<-chan Service, <-chan error) {
+	services := make(chan Service)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(services)
+
+		if err := c.streamServices(ctx, services); err != nil {
+			errc <- redactError(err, c.key, c.keyFor(ctx))
+		}
+	}()
+
+	return services, errc
+}
+
+// streamServices requests the list of services and sends each service on
+// services as it is decoded.
+func (c *JAPClient) streamServices(ctx context.Context, services chan<- Service) error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+
+	body := struct {
+		Key    string `json:"key"`
+		Action string `json:"action"`
+	}{
+		Key:    c.keyFor(ctx),
+		Action: "services",
+	}
+	bodyJSON, err := marshalBody(body)
+	if err != nil {
+		return err
+	}
+
+	resp, done, err := c.open(ctx, bodyJSON)
+	if err != nil {
+		return err
+	}
+	defer done()
+	br := bufio.NewReader(resp.Body)
+
+	// Anything but a JSON array is an error response; read it whole and report
+	// it like post would.
+	if first, err := peekNonSpace(br); err != nil || first != '[' || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		responseBody, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode <= 299 && !isJSONResponse(resp.Header.Get("Content-Type"), responseBody) {
+			return nonJSONResponseError(responseBody)
+		}
+		if err := checkResponse(responseBody, resp.StatusCode); err != nil {
+			return err
+		}
+		var response []Service
+		return json.Unmarshal(responseBody, &response)
+	}
+
+	dec := json.NewDecoder(br)
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		var s Service
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+
+		select {
+		case services <- s:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// peekNonSpace returns the first byte of br that isn't JSON whitespace,
+// without consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package jap
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tracer starts a span around each call, e.g. to correlate API latency with
+// the caller's traces. This package doesn't depend on OpenTelemetry; an adapter
+// implements Start by calling the Start method of an otel trace.Tracer and
+// converting attrs to attribute.String values.
+type Tracer interface {
+	// Start starts a span named name, a child of any span in ctx, with the
+	// given attributes. The returned context carries the new span.
+	Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End ends the span, recording err if it isn't nil.
+	End(err error)
+}
+
+// tracedFields are the request fields recorded as span attributes, mapped to
+// their attribute names.
+var tracedFields = map[string]string{
+	"service": "jap.service",
+	"order":   "jap.order",
+	"orders":  "jap.orders",
+	"refill":  "jap.refill",
+	"refills": "jap.refills",
+}
+
+// spanAttributes returns the span attributes for a JSON request body.
+func spanAttributes(action string, body []byte) map[string]string {
+	attrs := map[string]string{"jap.action": action}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return attrs
+	}
+	for field, name := range tracedFields {
+		if raw, ok := fields[field]; ok {
+			if value, _, err := decodeValueOrError(raw); err == nil && value != "" {
+				attrs[name] = value
+			}
+		}
+	}
+	return attrs
+}
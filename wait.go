@@ -0,0 +1,143 @@
+package jap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitForOrder polls the status of the order with the given order ID every
+// pollInterval until it reaches a terminal status (Completed, Partial or
+// Canceled) or the API reports an error for it, and returns that final status.
+// An error reported for the order is returned in the Error field of the status,
+// not as an error. Intervals shorter than a second are raised to a second.
+// WaitForOrder stops early if ctx or the base context set by
+// WithBaseContext is done.
+func (c *JAPClient) WaitForOrder(ctx context.Context, orderID string, pollInterval time.Duration) (OrderStatus, error) {
+	return c.WaitForOrderWithStrategy(ctx, orderID, ConstantPoll(pollInterval))
+}
+
+// minPollInterval is the shortest wait between two polls, so a zero or
+// negative interval can't make a loop hammer the API.
+const minPollInterval = time.Second
+
+// pollDelay returns d, raised to minPollInterval if it is shorter.
+func pollDelay(d time.Duration) time.Duration {
+	return max(d, minPollInterval)
+}
+
+// PollStrategy returns how long to wait after the given poll, numbered from 1,
+// before polling again. Waits shorter than a second are raised to a second.
+type PollStrategy func(poll int) time.Duration
+
+// ConstantPoll returns a PollStrategy that always waits interval.
+func ConstantPoll(interval time.Duration) PollStrategy {
+	return func(int) time.Duration {
+		return interval
+	}
+}
+
+// ExponentialPoll returns a PollStrategy that waits initial after the first
+// poll and doubles the wait after every poll, up to max. Quick orders are
+// then noticed soon, while long-running ones are polled sparingly.
+func ExponentialPoll(initial, max time.Duration) PollStrategy {
+	return func(poll int) time.Duration {
+		d := initial << (poll - 1)
+		if d <= 0 || d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// WaitForOrderWithStrategy is like WaitForOrder but waits between polls as
+// given by strategy, e.g. ExponentialPoll.
+func (c *JAPClient) WaitForOrderWithStrategy(ctx context.Context, orderID string, strategy PollStrategy) (OrderStatus, error) {
+	ctx, cancel := c.withBaseContext(ctx)
+	defer cancel()
+
+	for poll := 1; ; poll++ {
+		statuses, err := c.GetMultipleOrderStatusContext(ctx, []string{orderID})
+		if err != nil {
+			return OrderStatus{}, err
+		}
+
+		status, ok := statuses[orderID]
+		if !ok {
+			return OrderStatus{}, fmt.Errorf("jap: no status returned for order %s", orderID)
+		}
+		if isFinal(status) {
+			return status, nil
+		}
+
+		if err := sleepContext(ctx, pollDelay(strategy(poll))); err != nil {
+			return status, err
+		}
+	}
+}
+
+// WaitForOrders polls the statuses of the orders with the given order IDs every
+// pollInterval, in a single request per poll, until every order has reached a
+// terminal status or has an error reported for it, and returns the final
+// statuses keyed by order ID. Orders are dropped from later polls once final.
+// As for WaitForOrder, intervals shorter than a second are raised to a second.
+// If ctx or the base context set by WithBaseContext is done first, the statuses
+// of the orders that were final by then are returned along with the context's
+// error.
+func (c *JAPClient) WaitForOrders(ctx context.Context, orderIDs []string, pollInterval time.Duration) (map[string]OrderStatus, error) {
+	ctx, cancel := c.withBaseContext(ctx)
+	defer cancel()
+
+	final := make(map[string]OrderStatus, len(orderIDs))
+	pending := orderIDs
+	for len(pending) > 0 {
+		statuses, err := c.GetMultipleOrderStatusContext(ctx, pending)
+		if err != nil {
+			return final, err
+		}
+
+		var next []string
+		for _, orderID := range pending {
+			status, ok := statuses[orderID]
+			if !ok {
+				return final, fmt.Errorf("jap: no status returned for order %s", orderID)
+			}
+			if isFinal(status) {
+				final[orderID] = status
+			} else {
+				next = append(next, orderID)
+			}
+		}
+		pending = next
+		if len(pending) == 0 {
+			break
+		}
+
+		if err := sleepContext(ctx, pollDelay(pollInterval)); err != nil {
+			return final, err
+		}
+	}
+	return final, nil
+}
+
+// isFinal reports whether status won't change anymore, either because the
+// order reached a terminal status or because the API reported an error for it.
+func isFinal(status OrderStatus) bool {
+	return status.Error != "" || status.ParsedStatus().Terminal()
+}
+
+// withBaseContext returns a context derived from ctx that is also canceled
+// when the base context set by WithBaseContext is done, for long-running
+// loops. Values are still taken from ctx only. The returned cancel function
+// must be called once the loop ends.
+func (c *JAPClient) withBaseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if c.baseCtx == nil {
+		return ctx, cancel
+	}
+	stop := context.AfterFunc(c.baseCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}